@@ -68,7 +68,7 @@ func TestHTTPTransport_ServesRealSession(t *testing.T) {
 		apiServer.URL,
 		apiServer.Client(),
 	)
-	server := newServer(client)
+	server := newServer(client, serverOptions{})
 	httpServer := httptest.NewServer(buildHTTPHandler(server, []string{"127.0.0.1"}))
 	defer httpServer.Close()
 
@@ -88,8 +88,8 @@ func TestHTTPTransport_ServesRealSession(t *testing.T) {
 	if err != nil {
 		t.Fatalf("ListTools: %v", err)
 	}
-	if len(tools.Tools) != 3 {
-		t.Errorf("tools = %d, want 3", len(tools.Tools))
+	if len(tools.Tools) != 5 {
+		t.Errorf("tools = %d, want 5", len(tools.Tools))
 	}
 
 	result, err := session.CallTool(ctx, &mcp.CallToolParams{
@@ -115,7 +115,7 @@ func TestHTTPTransport_ServesHealth(t *testing.T) {
 		"",
 		"http://unused.invalid",
 		http.DefaultClient,
-	))
+	), serverOptions{})
 	httpServer := httptest.NewServer(buildHTTPHandler(server, []string{"127.0.0.1"}))
 	defer httpServer.Close()
 
@@ -155,7 +155,7 @@ func TestHTTPTransport_RejectsForgedCrossSiteOrigin(t *testing.T) {
 		"",
 		"http://unused.invalid",
 		http.DefaultClient,
-	))
+	), serverOptions{})
 	httpServer := httptest.NewServer(buildHTTPHandler(server, []string{"127.0.0.1"}))
 	defer httpServer.Close()
 
@@ -193,7 +193,7 @@ func TestHTTPTransport_ShutdownRequiresLoopbackBearerToken(t *testing.T) {
 			"",
 			"http://unused.invalid",
 			http.DefaultClient,
-		)),
+		), serverOptions{}),
 		[]string{"127.0.0.1"},
 		"secret-token",
 		func() { shutdownRequested = true },
@@ -262,7 +262,7 @@ func TestNewHTTPServer_DefaultsToLoopbackAddress(t *testing.T) {
 			"",
 			"http://unused.invalid",
 			http.DefaultClient,
-		)),
+		), serverOptions{}),
 		httpServerOptions{
 			ListenAddress: defaultHTTPListenAddress,
 			Port:          defaultHTTPPort,
@@ -0,0 +1,12 @@
+package config
+
+import "time"
+
+const envHistoricalBatchWindow = "GOOGLE_ADS_HISTORICAL_BATCH_WINDOW"
+
+// ResolveHistoricalBatchWindow returns how long a historical metrics lookup waits
+// for others to share its request, from the flag, then GOOGLE_ADS_HISTORICAL_BATCH_WINDOW,
+// then the .env file. It is a Go duration such as 50ms; unset disables batching.
+func ResolveHistoricalBatchWindow(flagVal string) (time.Duration, error) {
+	return resolveDuration("historical batch window", flagVal, envHistoricalBatchWindow, parseDotEnv())
+}
@@ -0,0 +1,106 @@
+package config
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	envCacheSize  = "GOOGLE_ADS_CACHE_SIZE"
+	envCacheTTL   = "GOOGLE_ADS_CACHE_TTL"
+	envCacheTools = "GOOGLE_ADS_CACHE_TOOLS"
+
+	envCacheDir      = "GOOGLE_ADS_CACHE_DIR"
+	envCacheDiskTTL  = "GOOGLE_ADS_CACHE_DISK_TTL"
+	envCacheMaxBytes = "GOOGLE_ADS_CACHE_MAX_BYTES"
+
+	// Defaults used when a setting is not configured.
+	defaultCacheSize = 500
+	defaultCacheTTL  = 15 * time.Minute
+
+	defaultCacheDiskTTL  = 24 * time.Hour
+	defaultCacheMaxBytes = 100 << 20
+)
+
+// Cache holds the resolved response cache settings.
+type Cache struct {
+	// Size is the most responses kept; 0 disables the cache.
+	Size int
+	// TTL is how long a response is served from the cache.
+	TTL time.Duration
+	// Tools names the tools whose responses are cached; empty means every tool.
+	Tools []string
+	// Dir is the directory of the on-disk cache; empty disables it.
+	Dir string
+	// DiskTTL is how long a response is served from the on-disk cache.
+	DiskTTL time.Duration
+	// MaxBytes caps the size of the on-disk cache; 0 means no cap.
+	MaxBytes int64
+}
+
+// CacheFlags holds cache values parsed from CLI flags, as strings so that unset
+// flags can fall through to the environment.
+type CacheFlags struct {
+	Size     string
+	TTL      string
+	Tools    string
+	Dir      string
+	DiskTTL  string
+	MaxBytes string
+}
+
+// ResolveCache returns the response cache settings from flags, then environment
+// variables, then the .env file, then defaults:
+//   - Size: GOOGLE_ADS_CACHE_SIZE (responses kept, default 500; 0 disables caching)
+//   - TTL: GOOGLE_ADS_CACHE_TTL (a Go duration, default 15m)
+//   - Tools: GOOGLE_ADS_CACHE_TOOLS (comma-separated tool names, default all tools)
+//   - Dir: GOOGLE_ADS_CACHE_DIR (default none, which disables the on-disk cache)
+//   - Disk TTL: GOOGLE_ADS_CACHE_DISK_TTL (a Go duration, default 24h)
+//   - Max bytes: GOOGLE_ADS_CACHE_MAX_BYTES (default 104857600; 0 means no cap)
+func ResolveCache(flags CacheFlags) (Cache, error) {
+	dotenv := parseDotEnv()
+
+	c := Cache{
+		Size:     defaultCacheSize,
+		TTL:      defaultCacheTTL,
+		Dir:      resolve("cache dir", flags.Dir, envCacheDir, dotenv),
+		DiskTTL:  defaultCacheDiskTTL,
+		MaxBytes: defaultCacheMaxBytes,
+	}
+	if v := resolve("cache size", flags.Size, envCacheSize, dotenv); v != "" {
+		size, err := strconv.Atoi(v)
+		if err != nil || size < 0 {
+			return Cache{}, fmt.Errorf("cache size must be a non-negative integer, got %q", v)
+		}
+		c.Size = size
+	}
+	ttl, err := resolveDuration("cache TTL", flags.TTL, envCacheTTL, dotenv)
+	if err != nil {
+		return Cache{}, err
+	}
+	if ttl > 0 {
+		c.TTL = ttl
+	}
+	diskTTL, err := resolveDuration("cache disk TTL", flags.DiskTTL, envCacheDiskTTL, dotenv)
+	if err != nil {
+		return Cache{}, err
+	}
+	if diskTTL > 0 {
+		c.DiskTTL = diskTTL
+	}
+	if v := resolve("cache max bytes", flags.MaxBytes, envCacheMaxBytes, dotenv); v != "" {
+		maxBytes, err := strconv.ParseInt(v, 10, 64)
+		if err != nil || maxBytes < 0 {
+			return Cache{}, fmt.Errorf("cache max bytes must be a non-negative integer, got %q", v)
+		}
+		c.MaxBytes = maxBytes
+	}
+	for _, tool := range strings.Split(resolve("cache tools", flags.Tools, envCacheTools, dotenv), ",") {
+		if tool = strings.TrimSpace(tool); tool != "" {
+			c.Tools = append(c.Tools, tool)
+		}
+	}
+	return c, nil
+}
@@ -1,4 +1,4 @@
-// Package config resolves Google Ads API credentials from multiple sources.
+// Package config resolves Google Ads API credentials and client settings from multiple sources.
 // Priority order: CLI flags > environment variables > .env file.
 //
 // Required credentials:
@@ -0,0 +1,88 @@
+package config
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+)
+
+const (
+	envRateLimit       = "GOOGLE_ADS_RATE_LIMIT"
+	envRateBurst       = "GOOGLE_ADS_RATE_BURST"
+	envDailyOperations = "GOOGLE_ADS_DAILY_OPERATIONS"
+	envQuotaStateFile  = "GOOGLE_ADS_QUOTA_STATE_FILE"
+
+	// Defaults used when a setting is not configured. They match the limits of a
+	// Basic access developer token.
+	defaultRateLimit       = 1.0
+	defaultRateBurst       = 3
+	defaultDailyOperations = 15_000
+)
+
+// Limits holds the resolved client-side request limits.
+type Limits struct {
+	// RateLimit is the requests per second allowed to each endpoint; 0 disables it.
+	RateLimit float64
+	// RateBurst is the number of requests an idle endpoint accepts at once.
+	RateBurst int
+	// DailyOperations caps requests per quota day; 0 disables the cap.
+	DailyOperations int
+	// QuotaStateFile keeps the daily operation count across restarts and shares it
+	// between processes; empty keeps it in memory only.
+	QuotaStateFile string
+}
+
+// LimitsFlags holds limit values parsed from CLI flags, as strings so that unset
+// flags can fall through to the environment.
+type LimitsFlags struct {
+	RateLimit       string
+	RateBurst       string
+	DailyOperations string
+	QuotaStateFile  string
+}
+
+// ResolveLimits returns the request limits from flags, then environment variables,
+// then the .env file, then defaults:
+//   - Rate limit: GOOGLE_ADS_RATE_LIMIT (requests per second per endpoint, default 1)
+//   - Rate burst: GOOGLE_ADS_RATE_BURST (default 3)
+//   - Daily operations: GOOGLE_ADS_DAILY_OPERATIONS (default 15000)
+//   - Quota state file: GOOGLE_ADS_QUOTA_STATE_FILE (default quota.json in the
+//     user cache directory)
+func ResolveLimits(flags LimitsFlags) (Limits, error) {
+	dotenv := parseDotEnv()
+
+	l := Limits{
+		RateLimit:       defaultRateLimit,
+		RateBurst:       defaultRateBurst,
+		DailyOperations: defaultDailyOperations,
+		QuotaStateFile:  resolve("quota state file", flags.QuotaStateFile, envQuotaStateFile, dotenv),
+	}
+	if v := resolve("rate limit", flags.RateLimit, envRateLimit, dotenv); v != "" {
+		rate, err := strconv.ParseFloat(v, 64)
+		if err != nil || rate < 0 {
+			return Limits{}, fmt.Errorf("rate limit must be a non-negative number, got %q", v)
+		}
+		l.RateLimit = rate
+	}
+	if v := resolve("rate burst", flags.RateBurst, envRateBurst, dotenv); v != "" {
+		burst, err := strconv.Atoi(v)
+		if err != nil || burst < 1 {
+			return Limits{}, fmt.Errorf("rate burst must be a positive integer, got %q", v)
+		}
+		l.RateBurst = burst
+	}
+	if v := resolve("daily operations", flags.DailyOperations, envDailyOperations, dotenv); v != "" {
+		ops, err := strconv.Atoi(v)
+		if err != nil || ops < 0 {
+			return Limits{}, fmt.Errorf("daily operations must be a non-negative integer, got %q", v)
+		}
+		l.DailyOperations = ops
+	}
+	if l.QuotaStateFile == "" {
+		if dir, err := os.UserCacheDir(); err == nil {
+			l.QuotaStateFile = filepath.Join(dir, "google-keyword-planner-mcp", "quota.json")
+		}
+	}
+	return l, nil
+}
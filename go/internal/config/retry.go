@@ -0,0 +1,82 @@
+package config
+
+import (
+	"fmt"
+	"strconv"
+	"time"
+)
+
+const (
+	envRetryMaxAttempts    = "GOOGLE_ADS_RETRY_MAX_ATTEMPTS"
+	envRetryInitialBackoff = "GOOGLE_ADS_RETRY_INITIAL_BACKOFF"
+	envRetryMaxBackoff     = "GOOGLE_ADS_RETRY_MAX_BACKOFF"
+)
+
+// Retry holds the resolved retry settings for Google Ads API calls. Zero fields were
+// not configured and take the client's defaults.
+type Retry struct {
+	// MaxAttempts is the total number of attempts per request; 1 disables retries.
+	MaxAttempts int
+	// InitialBackoff is the delay before the first retry.
+	InitialBackoff time.Duration
+	// MaxBackoff caps the delay between attempts.
+	MaxBackoff time.Duration
+}
+
+// RetryFlags holds retry values parsed from CLI flags, as strings so that unset
+// flags can fall through to the environment.
+type RetryFlags struct {
+	MaxAttempts    string
+	InitialBackoff string
+	MaxBackoff     string
+}
+
+// ResolveRetry returns the retry settings from flags, then environment variables,
+// then the .env file:
+//   - Max attempts: GOOGLE_ADS_RETRY_MAX_ATTEMPTS (e.g. 3)
+//   - Initial backoff: GOOGLE_ADS_RETRY_INITIAL_BACKOFF (a Go duration, e.g. 1s)
+//   - Max backoff: GOOGLE_ADS_RETRY_MAX_BACKOFF (a Go duration, e.g. 30s)
+func ResolveRetry(flags RetryFlags) (Retry, error) {
+	dotenv := parseDotEnv()
+
+	var r Retry
+	var err error
+	if r.MaxAttempts, err = resolvePositiveInt("retry max attempts", flags.MaxAttempts, envRetryMaxAttempts, dotenv); err != nil {
+		return Retry{}, err
+	}
+	if r.InitialBackoff, err = resolveDuration("retry initial backoff", flags.InitialBackoff, envRetryInitialBackoff, dotenv); err != nil {
+		return Retry{}, err
+	}
+	if r.MaxBackoff, err = resolveDuration("retry max backoff", flags.MaxBackoff, envRetryMaxBackoff, dotenv); err != nil {
+		return Retry{}, err
+	}
+	return r, nil
+}
+
+// resolvePositiveInt resolves a setting like resolve and parses it as a positive
+// integer. An unset setting is 0.
+func resolvePositiveInt(name, flagVal, envVar string, dotenv map[string]string) (int, error) {
+	v := resolve(name, flagVal, envVar, dotenv)
+	if v == "" {
+		return 0, nil
+	}
+	n, err := strconv.Atoi(v)
+	if err != nil || n <= 0 {
+		return 0, fmt.Errorf("%s must be a positive integer, got %q", name, v)
+	}
+	return n, nil
+}
+
+// resolveDuration resolves a setting like resolve and parses it as a positive Go
+// duration such as "500ms" or "2m". An unset setting is 0.
+func resolveDuration(name, flagVal, envVar string, dotenv map[string]string) (time.Duration, error) {
+	v := resolve(name, flagVal, envVar, dotenv)
+	if v == "" {
+		return 0, nil
+	}
+	d, err := time.ParseDuration(v)
+	if err != nil || d <= 0 {
+		return 0, fmt.Errorf("%s must be a positive duration such as 500ms or 2s, got %q", name, v)
+	}
+	return d, nil
+}
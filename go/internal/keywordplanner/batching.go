@@ -0,0 +1,82 @@
+package keywordplanner
+
+import (
+	"context"
+	"sync"
+)
+
+// Per-request limits of the Keyword Planner endpoints. Client methods split larger
+// inputs into chunks of at most these sizes and merge the results.
+const (
+	// MaxSeedKeywordsPerRequest is the most seed keywords one generateKeywordIdeas
+	// request accepts.
+	MaxSeedKeywordsPerRequest = 20
+	// MaxHistoricalKeywordsPerRequest is the most keywords one
+	// generateKeywordHistoricalMetrics request accepts.
+	MaxHistoricalKeywordsPerRequest = 10_000
+	// MaxForecastKeywordsPerRequest is the most biddable keywords one
+	// generateKeywordForecastMetrics request accepts, across all its ad groups.
+	MaxForecastKeywordsPerRequest = 10_000
+)
+
+// batchConcurrency bounds the chunk requests one call has in flight.
+const batchConcurrency = 4
+
+// runConcurrently calls fn for every i in [0, n), with at most limit calls in
+// flight. The first failure cancels the calls still running or waiting; it is the
+// error returned, not the cancellations it causes.
+func runConcurrently(ctx context.Context, n, limit int, fn func(ctx context.Context, i int) error) error {
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	var (
+		mu       sync.Mutex
+		firstErr error
+	)
+	sem := make(chan struct{}, limit)
+	var wg sync.WaitGroup
+	for i := range n {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			select {
+			case sem <- struct{}{}:
+			case <-ctx.Done():
+				return
+			}
+			defer func() { <-sem }()
+
+			if err := fn(ctx, i); err != nil {
+				mu.Lock()
+				if firstErr == nil {
+					firstErr = err
+				}
+				mu.Unlock()
+				cancel()
+			}
+		}()
+	}
+	wg.Wait()
+	if firstErr != nil {
+		return firstErr
+	}
+	return ctx.Err()
+}
+
+// mergeDeviceSearches totals the search counts of each device across parts, keeping
+// devices in the order they first appear.
+func mergeDeviceSearches(parts [][]DeviceSearches) []DeviceSearches {
+	var merged []DeviceSearches
+	index := make(map[string]int)
+	for _, part := range parts {
+		for _, d := range part {
+			if i, ok := index[d.Device]; ok {
+				merged[i].SearchCount += d.SearchCount
+				continue
+			}
+			index[d.Device] = len(merged)
+			merged = append(merged, d)
+		}
+	}
+	return merged
+}
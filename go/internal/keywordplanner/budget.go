@@ -0,0 +1,241 @@
+package keywordplanner
+
+import (
+	"cmp"
+	"context"
+	"fmt"
+	"math"
+	"slices"
+)
+
+// Defaults for the bid range Client.PlanForBudget searches.
+const (
+	DefaultBudgetMinCPCMicros = 10_000
+	DefaultBudgetMaxCPCMicros = 50_000_000
+)
+
+const (
+	// budgetSearchPrecisionMicros is the bid resolution the budget search stops at.
+	budgetSearchPrecisionMicros = 10_000
+	// maxBudgetSearchSteps caps the forecasts made by one bid search.
+	maxBudgetSearchSteps = 12
+)
+
+// BudgetPlanRequest holds the parameters for Client.PlanForBudget.
+type BudgetPlanRequest struct {
+	// Forecast describes the campaign to plan. Its MaxCPCMicros is searched over, so
+	// the value set here is ignored, and it must use manual CPC bidding.
+	Forecast ForecastRequest
+	// BudgetMicros is the total spend available over the forecast period.
+	BudgetMicros int64
+	// MinCPCMicros and MaxCPCMicros bound the bids searched; zero means
+	// DefaultBudgetMinCPCMicros and DefaultBudgetMaxCPCMicros.
+	MinCPCMicros int64
+	MaxCPCMicros int64
+}
+
+// BudgetPlanResponse is the result of Client.PlanForBudget.
+type BudgetPlanResponse struct {
+	BudgetMicros    int64                    `json:"budgetMicros"`
+	MaxCPCMicros    int64                    `json:"maxCpcMicros"`
+	Campaign        ForecastMetrics          `json:"campaign"`
+	Keywords        []KeywordForecastMetrics `json:"keywords"`
+	DroppedKeywords []KeywordForecastMetrics `json:"droppedKeywords,omitempty"`
+	BudgetLimited   bool                     `json:"budgetLimited"`
+	StartDate       string                   `json:"startDate"`
+	EndDate         string                   `json:"endDate"`
+	ForecastDays    int                      `json:"forecastDays"`
+	CacheInfo
+}
+
+// PlanForBudget finds the highest max CPC bid whose forecast cost fits the budget,
+// by binary search on the forecast cost curve. BudgetLimited reports whether the
+// budget, rather than the top of the bid range, set the bid. When even the lowest
+// bid overspends, the keywords costing the most per click are dropped until the
+// rest fit; they are listed in DroppedKeywords with their metrics at that bid.
+func (c *Client) PlanForBudget(ctx context.Context, req BudgetPlanRequest) (*BudgetPlanResponse, error) {
+	if err := requireManualCPC(req.Forecast.BiddingStrategy); err != nil {
+		return nil, err
+	}
+	if req.BudgetMicros <= 0 {
+		return nil, fmt.Errorf("budget must be positive, got %d", req.BudgetMicros)
+	}
+	minCPC, maxCPC := req.MinCPCMicros, req.MaxCPCMicros
+	if minCPC == 0 {
+		minCPC = DefaultBudgetMinCPCMicros
+	}
+	if maxCPC == 0 {
+		maxCPC = DefaultBudgetMaxCPCMicros
+	}
+	if minCPC <= 0 || maxCPC < minCPC {
+		return nil, fmt.Errorf("bid range must satisfy 0 < min <= max, got %d..%d", minCPC, maxCPC)
+	}
+
+	var cacheInfos []CacheInfo
+	forecastAt := func(forecastReq ForecastRequest, bid int64) (*ForecastResponse, error) {
+		forecastReq.MaxCPCMicros = bid
+		resp, err := c.GetKeywordForecast(ctx, forecastReq)
+		if err != nil {
+			return nil, fmt.Errorf("forecasting at %d micros: %w", bid, err)
+		}
+		cacheInfos = append(cacheInfos, resp.CacheInfo)
+		return resp, nil
+	}
+	fits := func(f *ForecastResponse) bool {
+		return f.Campaign.CostMicros <= float64(req.BudgetMicros)
+	}
+
+	forecastReq := req.Forecast
+	var dropped []KeywordForecastMetrics
+	low, err := forecastAt(forecastReq, minCPC)
+	if err != nil {
+		return nil, err
+	}
+	// Every pass removes at least one keyword or fails, so there are at most as many
+	// passes as keywords.
+	remaining := countForecastKeywords(forecastReq)
+	for !fits(low) {
+		if len(low.Keywords) == 0 {
+			return nil, fmt.Errorf("the forecast at the minimum bid of %d micros exceeds the budget of %d micros, and the API gave no per-keyword breakdown to choose keywords to drop",
+				minCPC, req.BudgetMicros)
+		}
+		drop := keywordsToDrop(low, float64(req.BudgetMicros))
+		next := withoutKeywords(forecastReq, drop)
+		n := countForecastKeywords(next)
+		if len(drop) == len(low.Keywords) || n == 0 {
+			return nil, fmt.Errorf("a budget of %d micros cannot cover any keyword, even at the minimum bid of %d micros",
+				req.BudgetMicros, minCPC)
+		}
+		if n == remaining {
+			return nil, fmt.Errorf("cannot drop %q to fit the budget: the forecast names keywords that are not in the request",
+				keywordTexts(drop))
+		}
+		remaining = n
+		dropped = append(dropped, drop...)
+		forecastReq = next
+		if low, err = forecastAt(forecastReq, minCPC); err != nil {
+			return nil, err
+		}
+	}
+
+	high, err := forecastAt(forecastReq, maxCPC)
+	if err != nil {
+		return nil, err
+	}
+	best, bestBid := low, minCPC
+	budgetLimited := !fits(high)
+	if budgetLimited {
+		lo, hi := minCPC, maxCPC
+		for step := 0; step < maxBudgetSearchSteps && hi-lo > budgetSearchPrecisionMicros; step++ {
+			mid := lo + (hi-lo)/2
+			mid -= mid % budgetSearchPrecisionMicros
+			if mid <= lo {
+				break
+			}
+			f, err := forecastAt(forecastReq, mid)
+			if err != nil {
+				return nil, err
+			}
+			if fits(f) {
+				lo, best, bestBid = mid, f, mid
+			} else {
+				hi = mid
+			}
+		}
+	} else {
+		best, bestBid = high, maxCPC
+	}
+
+	return &BudgetPlanResponse{
+		BudgetMicros:    req.BudgetMicros,
+		MaxCPCMicros:    bestBid,
+		Campaign:        best.Campaign,
+		Keywords:        best.Keywords,
+		DroppedKeywords: dropped,
+		BudgetLimited:   budgetLimited,
+		StartDate:       best.StartDate,
+		EndDate:         best.EndDate,
+		ForecastDays:    best.ForecastDays,
+		CacheInfo:       combineCacheInfo(cacheInfos...),
+	}, nil
+}
+
+// keywordsToDrop picks the keywords of f to remove so that the remaining forecast
+// cost fits budget, dropping the highest cost per click first. Keywords that cost
+// money without any clicks go before all others.
+func keywordsToDrop(f *ForecastResponse, budget float64) []KeywordForecastMetrics {
+	costPerClick := func(k KeywordForecastMetrics) float64 {
+		if k.Clicks <= 0 {
+			if k.CostMicros > 0 {
+				return math.Inf(1)
+			}
+			return 0
+		}
+		return k.CostMicros / k.Clicks
+	}
+	candidates := slices.Clone(f.Keywords)
+	slices.SortStableFunc(candidates, func(a, b KeywordForecastMetrics) int {
+		return cmp.Compare(costPerClick(b), costPerClick(a))
+	})
+
+	var drop []KeywordForecastMetrics
+	cost := f.Campaign.CostMicros
+	for _, k := range candidates {
+		if cost <= budget {
+			break
+		}
+		drop = append(drop, k)
+		cost -= k.CostMicros
+	}
+	// The per-keyword costs may not account for the whole campaign cost; always drop
+	// at least one keyword so the caller makes progress.
+	if len(drop) == 0 && len(candidates) > 0 {
+		drop = candidates[:1]
+	}
+	return drop
+}
+
+// withoutKeywords returns req with the dropped keywords removed from its keyword
+// list or ad groups. Ad groups left without keywords are removed too. The forecast
+// may echo keywords in a different case or spacing than requested, so texts are
+// compared normalized.
+func withoutKeywords(req ForecastRequest, dropped []KeywordForecastMetrics) ForecastRequest {
+	type keywordKey struct{ adGroup, text string }
+	drop := make(map[keywordKey]bool, len(dropped))
+	for _, k := range dropped {
+		drop[keywordKey{k.AdGroup, normalizeKeywordText(k.Text)}] = true
+	}
+	keep := func(adGroup string, keywords []ForecastKeyword) []ForecastKeyword {
+		return slices.DeleteFunc(slices.Clone(keywords), func(kw ForecastKeyword) bool {
+			return drop[keywordKey{adGroup, normalizeKeywordText(kw.Text)}]
+		})
+	}
+
+	req.Keywords = keep(DefaultAdGroupName, req.Keywords)
+	var adGroups []ForecastAdGroup
+	for _, group := range req.AdGroups {
+		group.Keywords = keep(group.Name, group.Keywords)
+		if len(group.Keywords) > 0 {
+			adGroups = append(adGroups, group)
+		}
+	}
+	req.AdGroups = adGroups
+	return req
+}
+
+// countForecastKeywords returns the number of keywords req forecasts.
+func countForecastKeywords(req ForecastRequest) int {
+	n := len(req.Keywords)
+	for _, group := range req.AdGroups {
+		n += len(group.Keywords)
+	}
+	return n
+}
+
+func keywordTexts(keywords []KeywordForecastMetrics) []string {
+	texts := make([]string, len(keywords))
+	for i, k := range keywords {
+		texts[i] = k.Text
+	}
+	return texts
+}
@@ -0,0 +1,144 @@
+package keywordplanner
+
+import (
+	"container/list"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"sync"
+	"time"
+)
+
+// CacheOptions configures the Client's in-memory response cache.
+type CacheOptions struct {
+	// MaxEntries is the most API responses kept; the least recently used is evicted
+	// first. 0 disables the cache.
+	MaxEntries int
+	// TTL is how long a response is served from the cache after it was fetched.
+	TTL time.Duration
+}
+
+// CacheInfo reports whether a result was served from the Client's cache. It is
+// embedded in the response types.
+type CacheInfo struct {
+	// Cached is true when every API response behind the result came from the cache.
+	Cached bool `json:"cached,omitempty"`
+	// FetchedAt is when the oldest of those responses was fetched from the API. It is
+	// set only when Cached is true.
+	FetchedAt time.Time `json:"fetched_at,omitzero"`
+}
+
+// SetCache caches successful API responses in memory, keyed on the customer ID, the
+// endpoint and the request body. Requests whose context comes from WithoutCache
+// bypass it. A zero MaxEntries or TTL turns the cache off.
+func (c *Client) SetCache(opts CacheOptions) {
+	if opts.MaxEntries <= 0 || opts.TTL <= 0 {
+		c.cache = nil
+		return
+	}
+	c.cache = &responseCache{
+		maxEntries: opts.MaxEntries,
+		ttl:        opts.TTL,
+		entries:    make(map[string]*list.Element),
+		order:      list.New(),
+	}
+}
+
+type withoutCacheKey struct{}
+
+// WithoutCache returns a context whose requests neither read from nor add to the
+// Client's cache.
+func WithoutCache(ctx context.Context) context.Context {
+	return context.WithValue(ctx, withoutCacheKey{}, true)
+}
+
+func cacheBypassed(ctx context.Context) bool {
+	bypass, _ := ctx.Value(withoutCacheKey{}).(bool)
+	return bypass
+}
+
+// cacheKey identifies a request by the customer it is made for, the RPC method of
+// its endpoint and its body. The body is marshalled from normalized values, so equal
+// requests produce equal bodies.
+func cacheKey(customerID, endpoint string, body []byte) string {
+	h := sha256.New()
+	h.Write([]byte(customerID))
+	h.Write([]byte{0})
+	h.Write([]byte(endpointName(endpoint)))
+	h.Write([]byte{0})
+	h.Write(body)
+	return hex.EncodeToString(h.Sum(nil))
+}
+
+// combineCacheInfo reports a result built from several API responses as cached only
+// when all of them were, as of the oldest fetch.
+func combineCacheInfo(infos ...CacheInfo) CacheInfo {
+	if len(infos) == 0 {
+		return CacheInfo{}
+	}
+	combined := infos[0]
+	for _, info := range infos {
+		if !info.Cached {
+			return CacheInfo{}
+		}
+		if info.FetchedAt.Before(combined.FetchedAt) {
+			combined.FetchedAt = info.FetchedAt
+		}
+	}
+	return combined
+}
+
+// responseCache is an LRU cache of raw response bodies whose entries expire after
+// ttl.
+type responseCache struct {
+	maxEntries int
+	ttl        time.Duration
+
+	mu      sync.Mutex
+	entries map[string]*list.Element
+	// order holds *cacheEntry values, most recently used first.
+	order *list.List
+}
+
+type cacheEntry struct {
+	key       string
+	body      []byte
+	fetchedAt time.Time
+}
+
+// get returns the unexpired entry for key, marking it most recently used. Expired
+// entries are removed.
+func (rc *responseCache) get(key string, now time.Time) (cacheEntry, bool) {
+	rc.mu.Lock()
+	defer rc.mu.Unlock()
+	el, ok := rc.entries[key]
+	if !ok {
+		return cacheEntry{}, false
+	}
+	entry := el.Value.(*cacheEntry)
+	if now.Sub(entry.fetchedAt) >= rc.ttl {
+		rc.order.Remove(el)
+		delete(rc.entries, key)
+		return cacheEntry{}, false
+	}
+	rc.order.MoveToFront(el)
+	return *entry, true
+}
+
+// put stores body under key, evicting the least recently used entries beyond
+// maxEntries.
+func (rc *responseCache) put(key string, body []byte, fetchedAt time.Time) {
+	rc.mu.Lock()
+	defer rc.mu.Unlock()
+	if el, ok := rc.entries[key]; ok {
+		el.Value = &cacheEntry{key: key, body: body, fetchedAt: fetchedAt}
+		rc.order.MoveToFront(el)
+		return
+	}
+	rc.entries[key] = rc.order.PushFront(&cacheEntry{key: key, body: body, fetchedAt: fetchedAt})
+	for rc.order.Len() > rc.maxEntries {
+		oldest := rc.order.Back()
+		rc.order.Remove(oldest)
+		delete(rc.entries, oldest.Value.(*cacheEntry).key)
+	}
+}
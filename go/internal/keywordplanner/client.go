@@ -8,6 +8,7 @@ import (
 	"fmt"
 	"io"
 	"net/http"
+	"slices"
 	"strconv"
 	"strings"
 	"time"
@@ -16,10 +17,49 @@ import (
 )
 
 const (
-	tokenURL    = "https://oauth2.googleapis.com/token"
-	adsAPIBase  = "https://googleads.googleapis.com/v23"
+	tokenURL      = "https://oauth2.googleapis.com/token"
+	adsAPIBase    = "https://googleads.googleapis.com/v23"
 	adsAPIVersion = "v23"
-	httpTimeout = 30 * time.Second
+	httpTimeout   = 30 * time.Second
+
+	dateLayout = "2006-01-02"
+
+	geoTargetConstantPrefix = "geoTargetConstants/"
+	languageConstantPrefix  = "languageConstants/"
+)
+
+// MaxKeywordIdeasPageSize is the largest page the generateKeywordIdeas endpoint returns.
+const MaxKeywordIdeasPageSize = 10_000
+
+// Keyword match types accepted by the forecast endpoint.
+const (
+	MatchTypeExact  = "EXACT"
+	MatchTypePhrase = "PHRASE"
+	MatchTypeBroad  = "BROAD"
+)
+
+// DefaultAdGroupName names the single ad group a forecast uses when the request lists
+// keywords directly rather than in ad groups.
+const DefaultAdGroupName = "default"
+
+// Bidding strategies supported for keyword forecasts.
+const (
+	BiddingStrategyManualCPC           = "MANUAL_CPC"
+	BiddingStrategyMaximizeClicks      = "MAXIMIZE_CLICKS"
+	BiddingStrategyMaximizeConversions = "MAXIMIZE_CONVERSIONS"
+)
+
+// MaxHistoricalMetricsMonths is how far back, in months, historical search volumes
+// are available.
+const MaxHistoricalMetricsMonths = 48
+
+// MaxForecastHorizonDays is how far ahead of today, in days, a forecast may end.
+const MaxForecastHorizonDays = 365
+
+// Keyword plan networks accepted by the Keyword Planner endpoints.
+const (
+	NetworkGoogleSearch            = "GOOGLE_SEARCH"
+	NetworkGoogleSearchAndPartners = "GOOGLE_SEARCH_AND_PARTNERS"
 )
 
 // Client calls the Google Ads Keyword Planner API.
@@ -30,6 +70,13 @@ type Client struct {
 	loginCustomerID string
 	baseURL         string
 	tokenSource     oauth2.TokenSource
+	now             func() time.Time
+	retry           RetryPolicy
+	limiter         *limiter
+	cache           *responseCache
+	diskCache       *diskCache
+	flights         flightGroup
+	batcher         *historicalBatcher
 }
 
 // NewClient creates a Client with the provided OAuth2 credentials.
@@ -58,10 +105,13 @@ func NewClientWithBaseURL(developerToken, clientID, clientSecret, refreshToken,
 		loginCustomerID: loginCustomerID,
 		baseURL:         baseURL,
 		tokenSource:     ts,
+		now:             time.Now,
+		retry:           DefaultRetryPolicy(),
 	}
 }
 
 // newTestClient creates a Client that uses a plain http.Client (no OAuth2) for unit tests.
+// It does not retry, so tests of failures stay fast; use SetRetryPolicy to opt in.
 func newTestClient(developerToken, customerID, loginCustomerID, baseURL string, httpClient *http.Client) *Client {
 	return &Client{
 		httpClient:      httpClient,
@@ -69,6 +119,8 @@ func newTestClient(developerToken, customerID, loginCustomerID, baseURL string,
 		customerID:      customerID,
 		loginCustomerID: loginCustomerID,
 		baseURL:         baseURL,
+		now:             time.Now,
+		retry:           RetryPolicy{MaxAttempts: 1},
 	}
 }
 
@@ -78,150 +130,800 @@ func NewTestClient(developerToken, customerID, loginCustomerID, baseURL string,
 	return newTestClient(developerToken, customerID, loginCustomerID, baseURL, httpClient)
 }
 
-// GenerateKeywordIdeas returns keyword ideas for the given seed keywords and/or URL.
-func (c *Client) GenerateKeywordIdeas(
-	ctx context.Context,
-	seedKeywords []string,
-	seedURL string,
-	language string,
-) (*KeywordIdeasResponse, error) {
-	reqBody := c.buildKeywordIdeasRequest(seedKeywords, seedURL, language)
-	endpoint := fmt.Sprintf("%s/customers/%s:generateKeywordIdeas", c.baseURL, c.customerID)
+// SetClock replaces the clock used to resolve relative dates, such as the default
+// forecast window and the current month for historical ranges. Intended for testing.
+func (c *Client) SetClock(now func() time.Time) {
+	c.now = now
+}
 
-	var raw generateKeywordIdeasResponse
-	if err := c.post(ctx, endpoint, reqBody, &raw); err != nil {
+// GenerateKeywordIdeas returns keyword ideas for the seed keywords and/or URL, or the
+// site, in req.
+// By default a single page is fetched; when req.MaxIdeas is positive, pages are
+// followed until that many ideas have been collected or the results run out.
+// More than MaxSeedKeywordsPerRequest seed keywords are split into chunks that are
+// requested concurrently, each with the same URL and options; the ideas are merged
+// in chunk order and report the chunk that produced them. A merged response has no
+// page token, and MaxIdeas applies to each chunk as well as to the merged list.
+func (c *Client) GenerateKeywordIdeas(ctx context.Context, req KeywordIdeasRequest) (*KeywordIdeasResponse, error) {
+	if req.Site != "" && (req.URL != "" || len(req.SeedKeywords) > 0) {
+		return nil, fmt.Errorf("site cannot be combined with a URL or seed keywords")
+	}
+	locations, err := NormalizeGeoTargetConstants(req.Locations)
+	if err != nil {
+		return nil, err
+	}
+	network, err := NormalizeNetwork(req.Network)
+	if err != nil {
 		return nil, err
 	}
+	if req.PageSize < 0 || req.PageSize > MaxKeywordIdeasPageSize {
+		return nil, fmt.Errorf("page size must be between 1 and %d (or 0 for the API default), got %d", MaxKeywordIdeasPageSize, req.PageSize)
+	}
+	if req.MaxIdeas < 0 {
+		return nil, fmt.Errorf("max ideas must not be negative, got %d", req.MaxIdeas)
+	}
+	seedChunks := [][]string{req.SeedKeywords}
+	if len(req.SeedKeywords) > MaxSeedKeywordsPerRequest {
+		if req.PageToken != "" {
+			return nil, fmt.Errorf("a page token cannot resume more than %d seed keywords, which are split across several requests",
+				MaxSeedKeywordsPerRequest)
+		}
+		seedChunks = slices.Collect(slices.Chunk(req.SeedKeywords, MaxSeedKeywordsPerRequest))
+	}
+	endpoint := fmt.Sprintf("%s/customers/%s:generateKeywordIdeas", c.baseURL, c.customerID)
 
-	ideas := make([]KeywordIdea, 0, len(raw.Results))
-	for _, r := range raw.Results {
-		ideas = append(ideas, KeywordIdea{
-			Text:                   r.Text,
-			AvgMonthlySearches:     parseI64(r.KeywordIdeaMetrics.AvgMonthlySearches),
-			Competition:            r.KeywordIdeaMetrics.Competition,
-			LowTopOfPageBidMicros:  parseI64(r.KeywordIdeaMetrics.LowTopOfPageBidMicros),
-			HighTopOfPageBidMicros: parseI64(r.KeywordIdeaMetrics.HighTopOfPageBidMicros),
+	results := make([]keywordIdeasResult, len(seedChunks))
+	fetchChunk := func(ctx context.Context, i, maxIdeas int) error {
+		reqBody := c.buildKeywordIdeasRequest(seedChunks[i], req.URL, req.Site, req.Language)
+		reqBody.GeoTargetConstants = locations
+		reqBody.KeywordPlanNetwork = network
+		reqBody.IncludeAdultKeywords = req.IncludeAdultKeywords
+		reqBody.AggregateMetrics = aggregateMetricsFor(req.BreakdownByDevice)
+		if req.IncludeConcepts {
+			reqBody.KeywordAnnotation = []string{"KEYWORD_CONCEPT"}
+		}
+		reqBody.PageToken = req.PageToken
+		result, err := c.collectKeywordIdeas(ctx, endpoint, reqBody, req.PageSize, maxIdeas)
+		if err != nil {
+			if len(seedChunks) > 1 {
+				return fmt.Errorf("seed chunk %d: %w", i+1, err)
+			}
+			return err
+		}
+		results[i] = result
+		return nil
+	}
+	if req.MaxIdeas > 0 && len(seedChunks) > 1 {
+		// MaxIdeas caps the merged list, so the chunks run one after another and each
+		// fetches only what the earlier chunks left room for.
+		for i := range seedChunks {
+			left := req.MaxIdeas - len(mergeKeywordIdeas(results[:i], 0))
+			if left <= 0 {
+				break
+			}
+			if err := fetchChunk(ctx, i, left); err != nil {
+				return nil, err
+			}
+		}
+	} else {
+		err = runConcurrently(ctx, len(seedChunks), batchConcurrency, func(ctx context.Context, i int) error {
+			return fetchChunk(ctx, i, req.MaxIdeas)
 		})
+		if err != nil {
+			return nil, err
+		}
 	}
 
-	return &KeywordIdeasResponse{
-		SeedKeywords: seedKeywords,
-		URL:          seedURL,
-		Ideas:        ideas,
-		Count:        len(ideas),
-	}, nil
+	resp := &KeywordIdeasResponse{
+		SeedKeywords:         req.SeedKeywords,
+		URL:                  req.URL,
+		Site:                 req.Site,
+		Locations:            locations,
+		Network:              network,
+		IncludeAdultKeywords: req.IncludeAdultKeywords,
+	}
+	if len(seedChunks) == 1 {
+		resp.Ideas = results[0].ideas
+		resp.NextPageToken = results[0].nextToken
+		resp.TotalSize = results[0].totalSize
+		resp.DeviceSearches = results[0].devices
+	} else {
+		resp.SeedChunks = seedChunks
+		// Each chunk's device counts cover its own ideas, and the chunks share ideas,
+		// so their totals cannot be added up; the breakdown is left out instead.
+		resp.Ideas = mergeKeywordIdeas(results, req.MaxIdeas)
+	}
+	if resp.Ideas == nil {
+		resp.Ideas = []KeywordIdea{}
+	}
+	resp.Count = len(resp.Ideas)
+	var pages []CacheInfo
+	for _, r := range results {
+		pages = append(pages, r.pages...)
+	}
+	resp.CacheInfo = combineCacheInfo(pages...)
+	return resp, nil
+}
+
+// keywordIdeasResult is what collectKeywordIdeas gathered for one request.
+type keywordIdeasResult struct {
+	ideas     []KeywordIdea
+	nextToken string
+	totalSize int64
+	devices   []DeviceSearches
+	// pages reports, per page fetched, whether it came from the cache.
+	pages []CacheInfo
+}
+
+// collectKeywordIdeas fetches a single page of ideas for reqBody, or when maxIdeas is
+// positive, follows pages until that many ideas have been collected or the results
+// run out.
+func (c *Client) collectKeywordIdeas(ctx context.Context, endpoint string, reqBody generateKeywordIdeasRequest, pageSize, maxIdeas int) (keywordIdeasResult, error) {
+	var result keywordIdeasResult
+	for {
+		reqBody.PageSize = keywordIdeasPageSize(pageSize, maxIdeas, len(result.ideas))
+
+		var raw generateKeywordIdeasResponse
+		page, err := c.post(ctx, endpoint, reqBody, &raw)
+		if err != nil {
+			return keywordIdeasResult{}, err
+		}
+		result.pages = append(result.pages, page)
+		for _, r := range raw.Results {
+			result.ideas = append(result.ideas, toKeywordIdea(r))
+		}
+		result.nextToken = raw.NextPageToken
+		result.totalSize = parseI64(raw.TotalSize)
+		if d := toDeviceSearches(raw.AggregateMetricResults); len(d) > 0 {
+			result.devices = d
+		}
+
+		if maxIdeas <= 0 || result.nextToken == "" || len(raw.Results) == 0 || len(result.ideas) >= maxIdeas {
+			return result, nil
+		}
+		reqBody.PageToken = result.nextToken
+	}
+}
+
+// mergeKeywordIdeas concatenates the ideas of each seed chunk in chunk order, marking
+// each with its 1-based chunk number. An idea produced by several chunks is kept once,
+// under the first. When maxIdeas is positive the merged list is cut to that length.
+func mergeKeywordIdeas(results []keywordIdeasResult, maxIdeas int) []KeywordIdea {
+	var ideas []KeywordIdea
+	seen := make(map[string]bool)
+	for i, r := range results {
+		for _, idea := range r.ideas {
+			if seen[idea.Text] {
+				continue
+			}
+			seen[idea.Text] = true
+			idea.SeedChunk = i + 1
+			ideas = append(ideas, idea)
+		}
+	}
+	if maxIdeas > 0 && len(ideas) > maxIdeas {
+		ideas = ideas[:maxIdeas]
+	}
+	return ideas
+}
+
+// keywordIdeasPageSize returns the pageSize to request for the next page. When a
+// MaxIdeas cap is in effect the page is shrunk to the remaining allowance, so the
+// cap is never overshot and the returned page token resumes exactly where the
+// collected ideas end.
+func keywordIdeasPageSize(pageSize, maxIdeas, collected int) int {
+	if maxIdeas <= 0 {
+		return pageSize
+	}
+	remaining := maxIdeas - collected
+	if pageSize == 0 || pageSize > remaining {
+		return min(remaining, MaxKeywordIdeasPageSize)
+	}
+	return pageSize
+}
+
+// aggregateMetricsFor returns the aggregateMetrics block requesting a DEVICE
+// breakdown, or nil when no breakdown was asked for.
+func aggregateMetricsFor(byDevice bool) *aggregateMetrics {
+	if !byDevice {
+		return nil
+	}
+	return &aggregateMetrics{AggregateMetricTypes: []string{"DEVICE"}}
 }
 
-// GetHistoricalMetrics returns historical search metrics for a list of keywords.
-func (c *Client) GetHistoricalMetrics(
-	ctx context.Context,
-	keywords []string,
-) (*HistoricalMetricsResponse, error) {
-	reqBody := generateHistoricalMetricsRequest{Keywords: keywords}
+func toDeviceSearches(results *aggregateMetricResults) []DeviceSearches {
+	if results == nil || len(results.DeviceSearches) == 0 {
+		return nil
+	}
+	out := make([]DeviceSearches, 0, len(results.DeviceSearches))
+	for _, d := range results.DeviceSearches {
+		out = append(out, DeviceSearches{Device: d.Device, SearchCount: parseI64(d.SearchCount)})
+	}
+	return out
+}
+
+func toKeywordIdea(r keywordIdeaResult) KeywordIdea {
+	return KeywordIdea{
+		Text:                   r.Text,
+		AvgMonthlySearches:     parseI64(r.KeywordIdeaMetrics.AvgMonthlySearches),
+		Competition:            r.KeywordIdeaMetrics.Competition,
+		CompetitionIndex:       int32(parseI64(r.KeywordIdeaMetrics.CompetitionIndex)),
+		LowTopOfPageBidMicros:  parseI64(r.KeywordIdeaMetrics.LowTopOfPageBidMicros),
+		HighTopOfPageBidMicros: parseI64(r.KeywordIdeaMetrics.HighTopOfPageBidMicros),
+		MonthlySearchVolumes:   toMonthlyVolumes(r.KeywordIdeaMetrics.MonthlySearchVolumes),
+		Concepts:               toKeywordConcepts(r.KeywordAnnotations.Concepts),
+	}
+}
+
+func toKeywordConcepts(raw []keywordConcept) []KeywordConcept {
+	if len(raw) == 0 {
+		return nil
+	}
+	concepts := make([]KeywordConcept, 0, len(raw))
+	for _, c := range raw {
+		concepts = append(concepts, KeywordConcept{
+			Name:      c.Name,
+			GroupName: c.ConceptGroup.Name,
+			GroupType: c.ConceptGroup.Type,
+		})
+	}
+	return concepts
+}
+
+func toMonthlyVolumes(raw []monthlySearchVolume) []MonthlyVolume {
+	monthly := make([]MonthlyVolume, 0, len(raw))
+	for _, m := range raw {
+		monthly = append(monthly, MonthlyVolume{
+			Year:            int32(m.Year),
+			Month:           parseMonthEnum(m.Month),
+			MonthlySearches: parseI64(m.MonthlySearches),
+		})
+	}
+	return monthly
+}
+
+// GetHistoricalMetrics returns historical search metrics for the keywords in req.
+// More than MaxHistoricalKeywordsPerRequest keywords are split into chunks that are
+// requested concurrently; the metrics are returned in keyword order and the device
+// breakdown is totalled across chunks. With SetHistoricalBatchWindow, lookups without
+// a device breakdown may share a request with other callers'.
+func (c *Client) GetHistoricalMetrics(ctx context.Context, req HistoricalMetricsRequest) (*HistoricalMetricsResponse, error) {
+	if err := validateYearMonthRange(req.StartMonth, req.EndMonth, c.now().UTC()); err != nil {
+		return nil, err
+	}
+	var opts *historicalMetricsOptions
+	if !req.StartMonth.IsZero() || req.IncludeAverageCPC {
+		opts = &historicalMetricsOptions{IncludeAverageCpc: req.IncludeAverageCPC}
+		if !req.StartMonth.IsZero() {
+			opts.YearMonthRange = &yearMonthRange{
+				Start: req.StartMonth.toAPI(),
+				End:   req.EndMonth.toAPI(),
+			}
+		}
+	}
 	endpoint := fmt.Sprintf("%s/customers/%s:generateKeywordHistoricalMetrics", c.baseURL, c.customerID)
 
-	var raw generateHistoricalMetricsResponse
-	if err := c.post(ctx, endpoint, reqBody, &raw); err != nil {
+	chunks := [][]string{req.Keywords}
+	if len(req.Keywords) > MaxHistoricalKeywordsPerRequest {
+		chunks = slices.Collect(slices.Chunk(req.Keywords, MaxHistoricalKeywordsPerRequest))
+	}
+	// The device breakdown totals a whole request, so it cannot be split between
+	// lookups batched together.
+	send := c.send
+	if c.batcher != nil && !req.BreakdownByDevice {
+		send = c.batcher.send
+	}
+	raws := make([]generateHistoricalMetricsResponse, len(chunks))
+	cacheInfos := make([]CacheInfo, len(chunks))
+	err := runConcurrently(ctx, len(chunks), batchConcurrency, func(ctx context.Context, i int) error {
+		reqBody := generateHistoricalMetricsRequest{
+			Keywords:                 chunks[i],
+			HistoricalMetricsOptions: opts,
+			AggregateMetrics:         aggregateMetricsFor(req.BreakdownByDevice),
+		}
+		var err error
+		if cacheInfos[i], err = c.postVia(ctx, endpoint, reqBody, &raws[i], send); err != nil {
+			if len(chunks) > 1 {
+				first := i * MaxHistoricalKeywordsPerRequest
+				return fmt.Errorf("keywords %d-%d: %w", first+1, first+len(chunks[i]), err)
+			}
+			return err
+		}
+		return nil
+	})
+	if err != nil {
 		return nil, err
 	}
 
-	metrics := make([]KeywordMetrics, 0, len(raw.Metrics))
-	for _, r := range raw.Metrics {
-		monthly := make([]MonthlyVolume, 0, len(r.KeywordMetrics.MonthlySearchVolumes))
-		for _, m := range r.KeywordMetrics.MonthlySearchVolumes {
-			monthly = append(monthly, MonthlyVolume{
-				Year:            m.Year,
-				Month:           parseMonthEnum(m.Month),
-				MonthlySearches: parseI64(m.MonthlySearches),
+	var metrics []KeywordMetrics
+	devices := make([][]DeviceSearches, len(raws))
+	for i, raw := range raws {
+		for _, r := range raw.Metrics {
+			metrics = append(metrics, KeywordMetrics{
+				Text:                   r.Text,
+				AvgMonthlySearches:     parseI64(r.KeywordMetrics.AvgMonthlySearches),
+				Competition:            r.KeywordMetrics.Competition,
+				CompetitionIndex:       r.KeywordMetrics.CompetitionIndex,
+				LowTopOfPageBidMicros:  parseI64(r.KeywordMetrics.LowTopOfPageBidMicros),
+				HighTopOfPageBidMicros: parseI64(r.KeywordMetrics.HighTopOfPageBidMicros),
+				AverageCPCMicros:       parseI64(r.KeywordMetrics.AverageCpcMicros),
+				MonthlySearchVolumes:   toMonthlyVolumes(r.KeywordMetrics.MonthlySearchVolumes),
 			})
 		}
-		metrics = append(metrics, KeywordMetrics{
-			Text:                   r.Text,
-			AvgMonthlySearches:     parseI64(r.KeywordMetrics.AvgMonthlySearches),
-			Competition:            r.KeywordMetrics.Competition,
-			CompetitionIndex:       r.KeywordMetrics.CompetitionIndex,
-			LowTopOfPageBidMicros:  parseI64(r.KeywordMetrics.LowTopOfPageBidMicros),
-			HighTopOfPageBidMicros: parseI64(r.KeywordMetrics.HighTopOfPageBidMicros),
-			MonthlySearchVolumes:   monthly,
-		})
+		devices[i] = toDeviceSearches(raw.AggregateMetricResults)
+	}
+	if metrics == nil {
+		metrics = []KeywordMetrics{}
 	}
 
-	return &HistoricalMetricsResponse{Keywords: metrics, Count: len(metrics)}, nil
+	resp := &HistoricalMetricsResponse{
+		Keywords:       metrics,
+		Count:          len(metrics),
+		DeviceSearches: mergeDeviceSearches(devices),
+		CacheInfo:      combineCacheInfo(cacheInfos...),
+	}
+	if !req.StartMonth.IsZero() {
+		resp.StartMonth = req.StartMonth.String()
+		resp.EndMonth = req.EndMonth.String()
+	}
+	return resp, nil
 }
 
-// GetKeywordForecast returns projected performance metrics for a set of keywords.
-func (c *Client) GetKeywordForecast(
-	ctx context.Context,
-	keywords []string,
-	maxCPCMicros int64,
-	forecastDays int,
-) (*ForecastResponse, error) {
-	if forecastDays <= 0 {
-		forecastDays = 30
+// GetKeywordForecast returns projected performance metrics for the keywords in req.
+// More than MaxForecastKeywordsPerRequest keywords are forecast in several requests,
+// made concurrently, whose results are totalled; ad groups too large for one request
+// are split between them. Such forecasts cannot use a daily budget, which would apply
+// to each request separately.
+func (c *Client) GetKeywordForecast(ctx context.Context, req ForecastRequest) (*ForecastResponse, error) {
+	startDate, endDate, err := resolveForecastPeriod(req.StartDate, req.EndDate, req.ForecastDays, c.now().UTC())
+	if err != nil {
+		return nil, err
 	}
+	maxCPCMicros := req.MaxCPCMicros
 	if maxCPCMicros <= 0 {
 		maxCPCMicros = 1_000_000
 	}
-	now := time.Now().UTC()
-	startDate := now.Format("2006-01-02")
-	endDate := now.AddDate(0, 0, forecastDays).Format("2006-01-02")
-
-	biddable := make([]adGroupForecastKeyword, 0, len(keywords))
-	for _, kw := range keywords {
-		biddable = append(biddable, adGroupForecastKeyword{
-			Keyword: forecastKeyword{Text: kw, MatchType: "BROAD"},
-		})
+	defaultMatchType, err := NormalizeMatchType(req.MatchType)
+	if err != nil {
+		return nil, err
 	}
+	locations, err := NormalizeGeoTargetConstants(req.Locations)
+	if err != nil {
+		return nil, err
+	}
+	languages, err := NormalizeLanguageConstants(req.Languages)
+	if err != nil {
+		return nil, err
+	}
+	network, err := NormalizeNetwork(req.Network)
+	if err != nil {
+		return nil, err
+	}
+	strategy, err := NormalizeBiddingStrategy(req.BiddingStrategy)
+	if err != nil {
+		return nil, err
+	}
+	if err := CheckForecastBids(strategy, req.Keywords, req.AdGroups); err != nil {
+		return nil, err
+	}
+	// The automated strategies set their own bids, so there is no max CPC to report.
+	reportedMaxCPC := maxCPCMicros
+	if strategy.Type != BiddingStrategyManualCPC {
+		reportedMaxCPC = 0
+	}
+	groups := req.AdGroups
+	if len(groups) == 0 {
+		groups = []ForecastAdGroup{{Name: DefaultAdGroupName, Keywords: req.Keywords}}
+	} else if len(req.Keywords) > 0 {
+		return nil, fmt.Errorf("keywords and ad groups cannot both be set; put the keywords in an ad group")
+	}
+	adGroups := make([]adGroupForecast, 0, len(groups))
+	requestedMatchTypes := make([]map[string]string, 0, len(groups))
+	seenNames := make(map[string]bool, len(groups))
+	for _, group := range groups {
+		if group.Name == "" {
+			return nil, fmt.Errorf("every ad group needs a name")
+		}
+		if seenNames[group.Name] {
+			return nil, fmt.Errorf("duplicate ad group name %q", group.Name)
+		}
+		seenNames[group.Name] = true
 
-	reqBody := generateForecastMetricsRequest{
-		CampaignForecastSpec: campaignForecastSpec{
-			BiddingStrategy: biddingStrategy{
-				ManualCpcBiddingStrategy: manualCpcBiddingStrategy{
-					MaxCPCBidMicros: strconv.FormatInt(maxCPCMicros, 10),
-				},
-			},
-			StartDate: startDate,
-			EndDate:   endDate,
-			AdGroups:  []adGroupForecast{{Biddable: biddable}},
-		},
+		adGroup, matchTypes, err := buildForecastAdGroup(group, defaultMatchType)
+		if err != nil {
+			return nil, fmt.Errorf("ad group %q: %w", group.Name, err)
+		}
+		adGroups = append(adGroups, adGroup)
+		requestedMatchTypes = append(requestedMatchTypes, matchTypes)
 	}
 
+	chunks := splitForecastAdGroups(adGroups, MaxForecastKeywordsPerRequest)
+	if len(chunks) > 1 && strategy.DailyBudgetMicros > 0 {
+		return nil, fmt.Errorf("more than %d keywords are forecast in several requests, which cannot share a daily budget",
+			MaxForecastKeywordsPerRequest)
+	}
 	endpoint := fmt.Sprintf("%s/customers/%s:generateKeywordForecastMetrics", c.baseURL, c.customerID)
 
-	var raw generateForecastMetricsResponse
-	if err := c.post(ctx, endpoint, reqBody, &raw); err != nil {
+	raws := make([]generateForecastMetricsResponse, len(chunks))
+	cacheInfos := make([]CacheInfo, len(chunks))
+	err = runConcurrently(ctx, len(chunks), batchConcurrency, func(ctx context.Context, i int) error {
+		specs := make([]adGroupForecast, len(chunks[i]))
+		for j, part := range chunks[i] {
+			specs[j] = part.spec
+		}
+		reqBody := generateForecastMetricsRequest{
+			CampaignForecastSpec: campaignForecastSpec{
+				BiddingStrategy:    strategy.toAPI(maxCPCMicros),
+				StartDate:          startDate.Format(dateLayout),
+				EndDate:            endDate.Format(dateLayout),
+				GeoModifiers:       toGeoModifiers(locations),
+				LanguageConstants:  languages,
+				KeywordPlanNetwork: network,
+				NegativeKeywords:   toNegativeKeywords(req.NegativeKeywords),
+				AdGroups:           specs,
+			},
+		}
+		var err error
+		if cacheInfos[i], err = c.post(ctx, endpoint, reqBody, &raws[i]); err != nil {
+			if len(chunks) > 1 {
+				return fmt.Errorf("keyword chunk %d: %w", i+1, err)
+			}
+			return err
+		}
+		return nil
+	})
+	if err != nil {
 		return nil, err
 	}
 
-	var forecastMetrics []KeywordForecastMetrics
-	for _, ag := range raw.AdGroupForecastMetrics {
-		for _, kf := range ag.KeywordForecastMetrics {
-			forecastMetrics = append(forecastMetrics, KeywordForecastMetrics{
-				Text:        kf.Keyword.Text,
-				Impressions: kf.Metrics.Impressions,
-				Clicks:      kf.Metrics.Clicks,
-				CostMicros:  kf.Metrics.CostMicros,
-				CTR:         kf.Metrics.CTR,
-			})
+	// The API reports ad groups in request order without echoing their names. An ad
+	// group split across requests is the total of its parts.
+	keywordMetrics := []KeywordForecastMetrics{}
+	groupParts := make([][]ForecastMetrics, len(groups))
+	chunkTotals := make([]ForecastMetrics, 0, len(chunks))
+	for i, raw := range raws {
+		var partTotals []ForecastMetrics
+		for j, ag := range raw.AdGroupForecastMetrics {
+			if j >= len(chunks[i]) {
+				break
+			}
+			group := chunks[i][j].group
+			var keywordTotals []ForecastMetrics
+			for _, kf := range ag.KeywordForecastMetrics {
+				matchType := kf.Keyword.MatchType
+				if matchType == "" {
+					matchType = requestedMatchTypes[group][kf.Keyword.Text]
+				}
+				metrics := kf.Metrics.toForecastMetrics()
+				keywordTotals = append(keywordTotals, metrics)
+				keywordMetrics = append(keywordMetrics, KeywordForecastMetrics{
+					Text:            kf.Keyword.Text,
+					MatchType:       matchType,
+					AdGroup:         groups[group].Name,
+					ForecastMetrics: metrics,
+				})
+			}
+			partMetrics := sumForecastMetrics(keywordTotals)
+			if ag.Metrics != nil {
+				partMetrics = ag.Metrics.toForecastMetrics()
+			}
+			groupParts[group] = append(groupParts[group], partMetrics)
+			partTotals = append(partTotals, partMetrics)
+		}
+		if raw.CampaignForecastMetrics != nil {
+			chunkTotals = append(chunkTotals, raw.CampaignForecastMetrics.toForecastMetrics())
+		} else {
+			chunkTotals = append(chunkTotals, sumForecastMetrics(partTotals))
+		}
+	}
+
+	adGroupMetrics := make(map[string]AdGroupForecastMetrics, len(groups))
+	for i, parts := range groupParts {
+		if len(parts) == 0 {
+			continue
 		}
+		metrics := parts[0]
+		if len(parts) > 1 {
+			metrics = sumForecastMetrics(parts)
+		}
+		adGroupMetrics[groups[i].Name] = AdGroupForecastMetrics{ForecastMetrics: metrics}
+	}
+	campaign := chunkTotals[0]
+	if len(chunkTotals) > 1 {
+		campaign = sumForecastMetrics(chunkTotals)
 	}
 
 	return &ForecastResponse{
-		Keywords:     forecastMetrics,
-		ForecastDays: forecastDays,
-		MaxCPCMicros: maxCPCMicros,
+		Campaign:         campaign,
+		AdGroups:         adGroupMetrics,
+		Keywords:         keywordMetrics,
+		StartDate:        startDate.Format(dateLayout),
+		EndDate:          endDate.Format(dateLayout),
+		ForecastDays:     int(endDate.Sub(startDate).Hours() / 24),
+		MaxCPCMicros:     reportedMaxCPC,
+		Locations:        locations,
+		Languages:        languages,
+		Network:          network,
+		NegativeKeywords: req.NegativeKeywords,
+		BiddingStrategy:  strategy,
+		CacheInfo:        combineCacheInfo(cacheInfos...),
 	}, nil
 }
 
-func (c *Client) post(ctx context.Context, endpoint string, body, out any) error {
+// resolveForecastPeriod returns the forecast window for the requested dates. start
+// defaults to today and end to forecastDays (default 30) after start. The window must
+// not start in the past nor end more than MaxForecastHorizonDays from today, which is
+// as far ahead as the API forecasts.
+func resolveForecastPeriod(start, end time.Time, forecastDays int, now time.Time) (time.Time, time.Time, error) {
+	today := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, time.UTC)
+	if !end.IsZero() && forecastDays > 0 {
+		return time.Time{}, time.Time{}, fmt.Errorf("forecast days cannot be combined with an end date")
+	}
+	if forecastDays <= 0 {
+		forecastDays = 30
+	}
+	if start.IsZero() {
+		start = today
+	}
+	if end.IsZero() {
+		end = start.AddDate(0, 0, forecastDays)
+	}
+	if start.Before(today) {
+		return time.Time{}, time.Time{}, fmt.Errorf("start date %s is in the past", start.Format(dateLayout))
+	}
+	if !end.After(start) {
+		return time.Time{}, time.Time{}, fmt.Errorf("end date %s must be after start date %s",
+			end.Format(dateLayout), start.Format(dateLayout))
+	}
+	if end.After(today.AddDate(0, 0, MaxForecastHorizonDays)) {
+		return time.Time{}, time.Time{}, fmt.Errorf("end date %s is more than %d days ahead; the API only forecasts up to a year out",
+			end.Format(dateLayout), MaxForecastHorizonDays)
+	}
+	return start, end, nil
+}
+
+// buildForecastAdGroup converts group to the wire format, resolving each keyword's
+// match type against defaultMatchType. It also returns the resolved match type per
+// keyword text, for labelling results the API returns without one.
+func buildForecastAdGroup(group ForecastAdGroup, defaultMatchType string) (adGroupForecast, map[string]string, error) {
+	if len(group.Keywords) == 0 {
+		return adGroupForecast{}, nil, fmt.Errorf("at least one keyword is required")
+	}
+	if group.MaxCPCMicros < 0 {
+		return adGroupForecast{}, nil, fmt.Errorf("max CPC bid must not be negative")
+	}
+	biddable := make([]adGroupForecastKeyword, 0, len(group.Keywords))
+	matchTypes := make(map[string]string, len(group.Keywords))
+	for _, kw := range group.Keywords {
+		matchType := defaultMatchType
+		if kw.MatchType != "" {
+			var err error
+			if matchType, err = NormalizeMatchType(kw.MatchType); err != nil {
+				return adGroupForecast{}, nil, fmt.Errorf("keyword %q: %w", kw.Text, err)
+			}
+		}
+		keyword := adGroupForecastKeyword{
+			Keyword:         forecastKeyword{Text: kw.Text, MatchType: matchType},
+			MaxCPCBidMicros: formatOptionalMicros(kw.MaxCPCMicros),
+		}
+		biddable = append(biddable, keyword)
+		if _, ok := matchTypes[kw.Text]; !ok {
+			matchTypes[kw.Text] = matchType
+		}
+	}
+	return adGroupForecast{
+		MaxCPCBidMicros:  formatOptionalMicros(group.MaxCPCMicros),
+		Biddable:         biddable,
+		NegativeKeywords: toNegativeKeywords(group.NegativeKeywords),
+	}, matchTypes, nil
+}
+
+// forecastPart is an ad group, or part of one, placed in a forecast request.
+type forecastPart struct {
+	// group is the index of the ad group in the request.
+	group int
+	spec  adGroupForecast
+}
+
+// splitForecastAdGroups packs adGroups, in order, into chunks of at most limit
+// biddable keywords. An ad group that does not fit in the current chunk is split,
+// and its remaining keywords start the next one.
+func splitForecastAdGroups(adGroups []adGroupForecast, limit int) [][]forecastPart {
+	var (
+		chunks  [][]forecastPart
+		current []forecastPart
+		size    int
+	)
+	for i, ag := range adGroups {
+		keywords := ag.Biddable
+		for len(keywords) > 0 {
+			n := min(len(keywords), limit-size)
+			part := ag
+			part.Biddable = keywords[:n]
+			current = append(current, forecastPart{group: i, spec: part})
+			keywords = keywords[n:]
+			if size += n; size == limit {
+				chunks = append(chunks, current)
+				current, size = nil, 0
+			}
+		}
+	}
+	if len(current) > 0 {
+		chunks = append(chunks, current)
+	}
+	return chunks
+}
+
+// sumForecastMetrics totals the additive metrics of parts and derives the rates and
+// averages from those totals. It backs campaign and ad group figures when the API
+// does not report them directly.
+func sumForecastMetrics(parts []ForecastMetrics) ForecastMetrics {
+	var total ForecastMetrics
+	for _, p := range parts {
+		total.Impressions += p.Impressions
+		total.Clicks += p.Clicks
+		total.CostMicros += p.CostMicros
+		total.Conversions += p.Conversions
+	}
+	if total.Impressions > 0 {
+		total.CTR = total.Clicks / total.Impressions
+	}
+	if total.Clicks > 0 {
+		total.AverageCPCMicros = total.CostMicros / total.Clicks
+		total.ConversionRate = total.Conversions / total.Clicks
+	}
+	if total.Conversions > 0 {
+		total.AverageCPAMicros = total.CostMicros / total.Conversions
+	}
+	return total
+}
+
+func toGeoModifiers(locations []string) []geoModifier {
+	if len(locations) == 0 {
+		return nil
+	}
+	modifiers := make([]geoModifier, 0, len(locations))
+	for _, loc := range locations {
+		modifiers = append(modifiers, geoModifier{GeoTargetConstant: loc})
+	}
+	return modifiers
+}
+
+// toNegativeKeywords sends each negative keyword as a broad match negative, which
+// blocks any query containing all of its words.
+func toNegativeKeywords(texts []string) []forecastKeyword {
+	if len(texts) == 0 {
+		return nil
+	}
+	negatives := make([]forecastKeyword, 0, len(texts))
+	for _, text := range texts {
+		negatives = append(negatives, forecastKeyword{Text: text, MatchType: MatchTypeBroad})
+	}
+	return negatives
+}
+
+// post sends body to endpoint and decodes the response into out. Each attempt is
+// subject to the client's Limits, and transient failures are retried according to
+// its RetryPolicy. When the client has a cache, an unexpired response to the same
+// request is decoded instead, and the returned CacheInfo says so. Concurrent calls
+// with the same endpoint and body share one request.
+func (c *Client) post(ctx context.Context, endpoint string, body, out any) (CacheInfo, error) {
+	return c.postVia(ctx, endpoint, body, out, c.send)
+}
+
+// postVia is post with the upstream request made by send, which takes the place of
+// Client.send after the caches and request coalescing.
+func (c *Client) postVia(ctx context.Context, endpoint string, body, out any, send sendFunc) (CacheInfo, error) {
 	reqBytes, err := json.Marshal(body)
 	if err != nil {
-		return fmt.Errorf("marshalling request: %w", err)
+		return CacheInfo{}, fmt.Errorf("marshalling request: %w", err)
+	}
+
+	var key string
+	var disk *diskCache
+	if !cacheBypassed(ctx) {
+		if c.diskCache != nil && diskCachedEndpoints[endpointName(endpoint)] {
+			disk = c.diskCache
+		}
+		if c.cache != nil || disk != nil {
+			key = cacheKey(c.customerID, endpoint, reqBytes)
+		}
+	}
+	if key != "" {
+		if info, ok, err := c.getCached(key, disk, out); ok || err != nil {
+			return info, err
+		}
+	}
+
+	respBody, shared, err := c.flights.do(ctx, endpoint+"\x00"+string(reqBytes), func() ([]byte, error) {
+		return send(ctx, endpoint, reqBytes)
+	})
+	if err != nil {
+		return CacheInfo{}, err
+	}
+
+	if err := json.Unmarshal(respBody, out); err != nil {
+		return CacheInfo{}, fmt.Errorf("parsing response: %w", err)
+	}
+	// The caller whose request was shared has already cached the response.
+	if key != "" && !shared {
+		fetchedAt := c.now().UTC().Truncate(time.Second)
+		if c.cache != nil {
+			c.cache.put(key, respBody, fetchedAt)
+		}
+		if disk != nil {
+			// A failed write only costs a later cache miss, which is no reason to fail
+			// the request.
+			_ = disk.put(key, respBody, fetchedAt)
+		}
 	}
+	return CacheInfo{}, nil
+}
 
+// sendFunc makes a request and returns the body of its 200 response.
+type sendFunc func(ctx context.Context, endpoint string, reqBytes []byte) ([]byte, error)
+
+// send makes the request, retrying transient failures, and returns the body of the
+// 200 response.
+func (c *Client) send(ctx context.Context, endpoint string, reqBytes []byte) ([]byte, error) {
+	for attempt := 1; ; attempt++ {
+		if err := c.limiter.acquire(ctx, endpoint, c.now); err != nil {
+			return nil, err
+		}
+		respBody, err := c.postOnce(ctx, endpoint, reqBytes)
+		if err == nil {
+			return respBody, nil
+		}
+		delay, retry := c.retry.delay(ctx, attempt, err)
+		if !retry {
+			return nil, err
+		}
+		timer := time.NewTimer(delay)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return nil, err
+		case <-timer.C:
+		}
+	}
+}
+
+// getCached decodes the response cached under key into out, looking in the memory
+// cache and then in disk, when that is not nil. A response found on disk is added to
+// the memory cache.
+func (c *Client) getCached(key string, disk *diskCache, out any) (CacheInfo, bool, error) {
+	var (
+		body      []byte
+		fetchedAt time.Time
+		found     bool
+	)
+	if c.cache != nil {
+		if entry, ok := c.cache.get(key, c.now()); ok {
+			body, fetchedAt, found = entry.body, entry.fetchedAt, true
+		}
+	}
+	if !found && disk != nil {
+		if entry, ok := disk.get(key, c.now()); ok {
+			body, fetchedAt, found = entry.Body, entry.FetchedAt, true
+			if c.cache != nil {
+				c.cache.put(key, body, fetchedAt)
+			}
+		}
+	}
+	if !found {
+		return CacheInfo{}, false, nil
+	}
+	if err := json.Unmarshal(body, out); err != nil {
+		return CacheInfo{}, false, fmt.Errorf("parsing cached response: %w", err)
+	}
+	return CacheInfo{Cached: true, FetchedAt: fetchedAt}, true, nil
+}
+
+// postOnce makes a single POST attempt and returns the body of a 200 response.
+func (c *Client) postOnce(ctx context.Context, endpoint string, reqBytes []byte) ([]byte, error) {
 	req, err := http.NewRequestWithContext(ctx, http.MethodPost, endpoint, bytes.NewReader(reqBytes))
 	if err != nil {
-		return fmt.Errorf("building request: %w", err)
+		return nil, fmt.Errorf("building request: %w", err)
 	}
 	req.Header.Set("Content-Type", "application/json")
 	req.Header.Set("developer-token", c.developerToken)
@@ -231,29 +933,26 @@ func (c *Client) post(ctx context.Context, endpoint string, body, out any) error
 
 	resp, err := c.httpClient.Do(req)
 	if err != nil {
-		return fmt.Errorf("executing request: %w", err)
+		return nil, fmt.Errorf("executing request: %w", err)
 	}
 	defer func() { _ = resp.Body.Close() }()
 
 	respBody, err := io.ReadAll(resp.Body)
 	if err != nil {
-		return fmt.Errorf("reading response: %w", err)
+		return nil, fmt.Errorf("reading response: %w", err)
 	}
 
 	if resp.StatusCode != http.StatusOK {
-		return fmt.Errorf("Google Ads API returned HTTP %d: %s",
-			resp.StatusCode, string(respBody))
-	}
-
-	if err := json.Unmarshal(respBody, out); err != nil {
-		return fmt.Errorf("parsing response: %w", err)
+		return nil, newAPIError(resp.StatusCode, resp.Header, respBody)
 	}
-	return nil
+	return respBody, nil
 }
 
-func (c *Client) buildKeywordIdeasRequest(seedKeywords []string, seedURL, language string) generateKeywordIdeasRequest {
+func (c *Client) buildKeywordIdeasRequest(seedKeywords []string, seedURL, site, language string) generateKeywordIdeasRequest {
 	req := generateKeywordIdeasRequest{Language: language}
 	switch {
+	case site != "":
+		req.SiteSeed = &siteSeed{Site: site}
 	case len(seedKeywords) > 0 && seedURL != "":
 		req.KeywordAndURLSeed = &keywordAndURLSeed{URL: seedURL, Keywords: seedKeywords}
 	case seedURL != "":
@@ -272,6 +971,193 @@ func parseI64(s string) int64 {
 	return v
 }
 
+// NormalizeGeoTargetConstants converts each location to its geoTargetConstants resource
+// name. Both "geoTargetConstants/2840" and the bare numeric ID "2840" are accepted.
+func NormalizeGeoTargetConstants(locations []string) ([]string, error) {
+	return normalizeResourceNames(locations, geoTargetConstantPrefix, "location", "2840")
+}
+
+// NormalizeLanguageConstants converts each language to its languageConstants resource
+// name. Both "languageConstants/1000" and the bare numeric ID "1000" are accepted.
+func NormalizeLanguageConstants(languages []string) ([]string, error) {
+	return normalizeResourceNames(languages, languageConstantPrefix, "language", "1000")
+}
+
+// normalizeResourceNames prefixes each numeric ID with prefix, accepting values that
+// already carry it. kind and exampleID are used in the validation error.
+func normalizeResourceNames(values []string, prefix, kind, exampleID string) ([]string, error) {
+	if len(values) == 0 {
+		return nil, nil
+	}
+	out := make([]string, 0, len(values))
+	for _, value := range values {
+		id := strings.TrimPrefix(strings.TrimSpace(value), prefix)
+		if v, err := strconv.ParseInt(id, 10, 64); err != nil || v <= 0 {
+			return nil, fmt.Errorf("invalid %s %q: want an ID such as %q or %q",
+				kind, value, exampleID, prefix+exampleID)
+		}
+		out = append(out, prefix+id)
+	}
+	return out, nil
+}
+
+// ParseYearMonth parses a "YYYY-MM" string such as "2024-01".
+func ParseYearMonth(s string) (YearMonth, error) {
+	t, err := time.Parse("2006-01", strings.TrimSpace(s))
+	if err != nil {
+		return YearMonth{}, fmt.Errorf("invalid month %q: want YYYY-MM (e.g. %q)", s, "2024-01")
+	}
+	return YearMonth{Year: t.Year(), Month: t.Month()}, nil
+}
+
+// ParseDate parses a "YYYY-MM-DD" string such as "2025-11-28".
+func ParseDate(s string) (time.Time, error) {
+	t, err := time.Parse(dateLayout, strings.TrimSpace(s))
+	if err != nil {
+		return time.Time{}, fmt.Errorf("invalid date %q: want YYYY-MM-DD (e.g. %q)", s, "2025-11-28")
+	}
+	return t, nil
+}
+
+// validateYearMonthRange checks that start and end are either both unset, or form an
+// ordered range inside the window the API serves: no earlier than
+// MaxHistoricalMetricsMonths before now and no later than the current month.
+func validateYearMonthRange(start, end YearMonth, now time.Time) error {
+	if start.IsZero() && end.IsZero() {
+		return nil
+	}
+	if start.IsZero() || end.IsZero() {
+		return fmt.Errorf("start month and end month must be provided together")
+	}
+	if end.index() < start.index() {
+		return fmt.Errorf("end month %s is before start month %s", end, start)
+	}
+	current := YearMonth{Year: now.Year(), Month: now.Month()}
+	if end.index() > current.index() {
+		return fmt.Errorf("end month %s is in the future", end)
+	}
+	if current.index()-start.index() > MaxHistoricalMetricsMonths {
+		return fmt.Errorf("start month %s is more than %d months ago; the API only serves the last 4 years",
+			start, MaxHistoricalMetricsMonths)
+	}
+	return nil
+}
+
+// NormalizeMatchType validates a keyword match type, case-insensitively, and returns
+// its canonical enum value. An empty match type resolves to BROAD.
+func NormalizeMatchType(matchType string) (string, error) {
+	switch m := strings.ToUpper(strings.TrimSpace(matchType)); m {
+	case "":
+		return MatchTypeBroad, nil
+	case MatchTypeExact, MatchTypePhrase, MatchTypeBroad:
+		return m, nil
+	default:
+		return "", fmt.Errorf("invalid match type %q: want %s, %s or %s",
+			matchType, MatchTypeExact, MatchTypePhrase, MatchTypeBroad)
+	}
+}
+
+// NormalizeBiddingStrategy validates s and returns it with its Type in canonical
+// form. MAXIMIZE_CLICKS and MAXIMIZE_CONVERSIONS need a positive daily budget, and
+// only MAXIMIZE_CLICKS accepts a CPC ceiling. The forecast API has no target CPA
+// input, so MAXIMIZE_CONVERSIONS is forecast from its daily budget alone.
+func NormalizeBiddingStrategy(s BiddingStrategy) (BiddingStrategy, error) {
+	s.Type = strings.ToUpper(strings.TrimSpace(s.Type))
+	if s.DailyBudgetMicros < 0 || s.MaxCPCCeilingMicros < 0 {
+		return BiddingStrategy{}, fmt.Errorf("bidding strategy amounts must not be negative")
+	}
+	switch s.Type {
+	case "", BiddingStrategyManualCPC:
+		s.Type = BiddingStrategyManualCPC
+		if s.MaxCPCCeilingMicros > 0 {
+			return BiddingStrategy{}, fmt.Errorf("%s does not take a CPC ceiling; set the max CPC bid instead", s.Type)
+		}
+	case BiddingStrategyMaximizeClicks:
+		if s.DailyBudgetMicros == 0 {
+			return BiddingStrategy{}, fmt.Errorf("%s requires a daily budget", s.Type)
+		}
+	case BiddingStrategyMaximizeConversions:
+		if s.DailyBudgetMicros == 0 {
+			return BiddingStrategy{}, fmt.Errorf("%s requires a daily budget", s.Type)
+		}
+		if s.MaxCPCCeilingMicros > 0 {
+			return BiddingStrategy{}, fmt.Errorf("%s does not take a CPC ceiling", s.Type)
+		}
+	default:
+		return BiddingStrategy{}, fmt.Errorf("invalid bidding strategy %q: want %s, %s or %s",
+			s.Type, BiddingStrategyManualCPC, BiddingStrategyMaximizeClicks, BiddingStrategyMaximizeConversions)
+	}
+	return s, nil
+}
+
+// CheckForecastBids reports an error when a keyword or ad group sets its own max
+// CPC bid under an automated strategy, which would otherwise be sent but ignored.
+// An empty strategy type means MANUAL_CPC.
+func CheckForecastBids(strategy BiddingStrategy, keywords []ForecastKeyword, groups []ForecastAdGroup) error {
+	if strategy.Type == "" || strings.EqualFold(strategy.Type, BiddingStrategyManualCPC) {
+		return nil
+	}
+	for _, kw := range keywords {
+		if kw.MaxCPCMicros != 0 {
+			return fmt.Errorf("keyword %q sets a max CPC bid, which needs %s bidding", kw.Text, BiddingStrategyManualCPC)
+		}
+	}
+	for _, group := range groups {
+		if group.MaxCPCMicros != 0 {
+			return fmt.Errorf("ad group %q sets a max CPC bid, which needs %s bidding", group.Name, BiddingStrategyManualCPC)
+		}
+		if err := CheckForecastBids(strategy, group.Keywords, nil); err != nil {
+			return fmt.Errorf("ad group %q: %w", group.Name, err)
+		}
+	}
+	return nil
+}
+
+// toAPI converts a normalized strategy to the campaign forecast wire format.
+// maxCPCMicros is the manual CPC bid and is ignored by the automated strategies.
+func (s BiddingStrategy) toAPI(maxCPCMicros int64) biddingStrategy {
+	switch s.Type {
+	case BiddingStrategyMaximizeClicks:
+		return biddingStrategy{MaximizeClicksBiddingStrategy: &maximizeClicksBiddingStrategy{
+			DailyTargetSpendMicros: strconv.FormatInt(s.DailyBudgetMicros, 10),
+			MaxCPCBidCeilingMicros: formatOptionalMicros(s.MaxCPCCeilingMicros),
+		}}
+	case BiddingStrategyMaximizeConversions:
+		return biddingStrategy{MaximizeConversionsBiddingStrategy: &maximizeConversionsBiddingStrategy{
+			DailyTargetSpendMicros: strconv.FormatInt(s.DailyBudgetMicros, 10),
+		}}
+	default:
+		return biddingStrategy{ManualCpcBiddingStrategy: &manualCpcBiddingStrategy{
+			DailyBudgetMicros: formatOptionalMicros(s.DailyBudgetMicros),
+			MaxCPCBidMicros:   strconv.FormatInt(maxCPCMicros, 10),
+		}}
+	}
+}
+
+// formatOptionalMicros formats a micros amount for an optional field, returning ""
+// (omitted) for zero.
+func formatOptionalMicros(micros int64) string {
+	if micros == 0 {
+		return ""
+	}
+	return strconv.FormatInt(micros, 10)
+}
+
+// NormalizeNetwork validates a keyword plan network name, case-insensitively, and
+// returns its canonical enum value. An empty network resolves to the API default of
+// GOOGLE_SEARCH_AND_PARTNERS so callers always know which network was used.
+func NormalizeNetwork(network string) (string, error) {
+	switch n := strings.ToUpper(strings.TrimSpace(network)); n {
+	case "":
+		return NetworkGoogleSearchAndPartners, nil
+	case NetworkGoogleSearch, NetworkGoogleSearchAndPartners:
+		return n, nil
+	default:
+		return "", fmt.Errorf("invalid network %q: want %s or %s",
+			network, NetworkGoogleSearch, NetworkGoogleSearchAndPartners)
+	}
+}
+
 // parseMonthEnum converts "JANUARY" → 1, etc.
 func parseMonthEnum(month string) int32 {
 	months := map[string]int32{
@@ -284,5 +1170,3 @@ func parseMonthEnum(month string) int32 {
 	}
 	return 0
 }
-
-
@@ -0,0 +1,62 @@
+package keywordplanner
+
+import (
+	"context"
+	"errors"
+	"sync"
+)
+
+// flightGroup coalesces concurrent identical requests: while one is in flight,
+// callers making the same request wait for its result instead of sending their own.
+// The zero value is ready to use.
+type flightGroup struct {
+	mu      sync.Mutex
+	flights map[string]*flight
+}
+
+// flight is one in-flight request and, once done is closed, its result.
+type flight struct {
+	done chan struct{}
+	body []byte
+	err  error
+}
+
+// do returns the result of fn for key, calling it only if no call for key is already
+// in flight; shared reports that another caller's result was returned. A caller
+// stops waiting when its own ctx ends. When the call it waited on failed only because
+// that caller's context ended, it makes the request itself rather than inherit the
+// cancellation.
+func (g *flightGroup) do(ctx context.Context, key string, fn func() ([]byte, error)) (body []byte, shared bool, err error) {
+	for {
+		g.mu.Lock()
+		if g.flights == nil {
+			g.flights = make(map[string]*flight)
+		}
+		if f, ok := g.flights[key]; ok {
+			g.mu.Unlock()
+			select {
+			case <-ctx.Done():
+				return nil, false, ctx.Err()
+			case <-f.done:
+			}
+			if isContextError(f.err) && ctx.Err() == nil {
+				continue
+			}
+			return f.body, true, f.err
+		}
+		f := &flight{done: make(chan struct{})}
+		g.flights[key] = f
+		g.mu.Unlock()
+
+		f.body, f.err = fn()
+		g.mu.Lock()
+		delete(g.flights, key)
+		g.mu.Unlock()
+		close(f.done)
+		return f.body, false, f.err
+	}
+}
+
+func isContextError(err error) bool {
+	return errors.Is(err, context.Canceled) || errors.Is(err, context.DeadlineExceeded)
+}
@@ -0,0 +1,45 @@
+package keywordplanner
+
+import (
+	"cmp"
+	"slices"
+)
+
+// GroupIdeasByConcept buckets ideas by the concept groups in their KEYWORD_CONCEPT
+// annotations. An idea annotated with concepts from several groups is listed in each
+// of them; ideas without annotations are left out. Groups are ordered by their
+// combined average monthly searches, highest first.
+func GroupIdeasByConcept(ideas []KeywordIdea) []ConceptGroup {
+	type groupKey struct{ name, typ string }
+	index := make(map[groupKey]int)
+	var groups []ConceptGroup
+
+	for _, idea := range ideas {
+		seen := make(map[groupKey]bool)
+		for _, concept := range idea.Concepts {
+			key := groupKey{concept.GroupName, concept.GroupType}
+			if seen[key] {
+				continue
+			}
+			seen[key] = true
+
+			i, ok := index[key]
+			if !ok {
+				i = len(groups)
+				index[key] = i
+				groups = append(groups, ConceptGroup{Name: key.name, Type: key.typ})
+			}
+			groups[i].Keywords = append(groups[i].Keywords, idea.Text)
+			groups[i].Count++
+			groups[i].AvgMonthlySearches += idea.AvgMonthlySearches
+		}
+	}
+
+	slices.SortStableFunc(groups, func(a, b ConceptGroup) int {
+		if c := cmp.Compare(b.AvgMonthlySearches, a.AvgMonthlySearches); c != 0 {
+			return c
+		}
+		return cmp.Compare(a.Name, b.Name)
+	})
+	return groups
+}
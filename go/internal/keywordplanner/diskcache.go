@@ -0,0 +1,221 @@
+package keywordplanner
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+)
+
+const (
+	diskCacheLockFile   = ".lock"
+	diskCacheTempPrefix = ".tmp-"
+	diskCacheEntryExt   = ".json"
+)
+
+// diskCachedEndpoints are the RPC methods whose responses are written to the disk
+// cache. Forecasts depend on the current date and are not kept across restarts.
+var diskCachedEndpoints = map[string]bool{
+	"generateKeywordIdeas":             true,
+	"generateKeywordHistoricalMetrics": true,
+}
+
+// DiskCacheOptions configures the Client's on-disk response cache.
+type DiskCacheOptions struct {
+	// Dir is the directory entries are stored in; empty disables the disk cache.
+	// Several processes may share it.
+	Dir string
+	// TTL is how long an entry is served after it was fetched. Each entry records its
+	// own expiry, so changing TTL affects only entries written afterwards.
+	TTL time.Duration
+	// MaxBytes caps the total size of the entries; the least recently used are
+	// removed first once it is exceeded. Zero means no cap.
+	MaxBytes int64
+}
+
+// SetDiskCache caches keyword idea and historical metrics responses in files under
+// opts.Dir, so that they survive restarts and are shared between processes. It is
+// consulted after the in-memory cache of SetCache, and requests whose context comes
+// from WithoutCache bypass it too.
+func (c *Client) SetDiskCache(opts DiskCacheOptions) error {
+	if opts.Dir == "" || opts.TTL <= 0 {
+		c.diskCache = nil
+		return nil
+	}
+	if opts.MaxBytes < 0 {
+		return fmt.Errorf("disk cache max bytes must not be negative, got %d", opts.MaxBytes)
+	}
+	if err := os.MkdirAll(opts.Dir, 0o755); err != nil {
+		return fmt.Errorf("creating cache directory: %w", err)
+	}
+	c.diskCache = &diskCache{dir: opts.Dir, ttl: opts.TTL, maxBytes: opts.MaxBytes}
+	return nil
+}
+
+// diskCache stores one file per response. Entries are replaced by renaming a
+// complete temporary file over them, so readers never see a partial write and need
+// no lock. Writers and eviction hold a lock file, which keeps the size accounting of
+// concurrent processes consistent.
+type diskCache struct {
+	dir      string
+	ttl      time.Duration
+	maxBytes int64
+
+	// mu serializes this process's holders of the lock file, so that they queue
+	// rather than poll it.
+	mu sync.Mutex
+}
+
+// diskCacheEntry is the format of an entry file.
+type diskCacheEntry struct {
+	FetchedAt time.Time       `json:"fetchedAt"`
+	ExpiresAt time.Time       `json:"expiresAt"`
+	Body      json.RawMessage `json:"body"`
+}
+
+func (d *diskCache) path(key string) string {
+	return filepath.Join(d.dir, key+diskCacheEntryExt)
+}
+
+// get returns the unexpired entry for key. A hit refreshes the file's modification
+// time, which eviction treats as its last use; an expired entry is removed.
+func (d *diskCache) get(key string, now time.Time) (diskCacheEntry, bool) {
+	path := d.path(key)
+	entry, err := readDiskCacheEntry(path)
+	if err != nil {
+		return diskCacheEntry{}, false
+	}
+	if !now.Before(entry.ExpiresAt) {
+		d.removeExpired(path, now)
+		return diskCacheEntry{}, false
+	}
+	t := time.Now()
+	_ = os.Chtimes(path, t, t)
+	return entry, true
+}
+
+// removeExpired deletes the entry at path unless another process has replaced it
+// with a fresh one since it was read.
+func (d *diskCache) removeExpired(path string, now time.Time) {
+	unlock, err := d.lock()
+	if err != nil {
+		return
+	}
+	defer unlock()
+	if entry, err := readDiskCacheEntry(path); err == nil && !now.Before(entry.ExpiresAt) {
+		_ = os.Remove(path)
+	}
+}
+
+// put stores body under key, then evicts entries beyond maxBytes.
+func (d *diskCache) put(key string, body []byte, fetchedAt time.Time) error {
+	b, err := json.Marshal(diskCacheEntry{FetchedAt: fetchedAt, ExpiresAt: fetchedAt.Add(d.ttl), Body: body})
+	if err != nil {
+		return fmt.Errorf("marshalling cache entry: %w", err)
+	}
+	tmp, err := os.CreateTemp(d.dir, diskCacheTempPrefix+"*")
+	if err != nil {
+		return fmt.Errorf("writing cache entry: %w", err)
+	}
+	_, err = tmp.Write(b)
+	if closeErr := tmp.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		_ = os.Remove(tmp.Name())
+		return fmt.Errorf("writing cache entry: %w", err)
+	}
+
+	unlock, err := d.lock()
+	if err != nil {
+		_ = os.Remove(tmp.Name())
+		return err
+	}
+	defer unlock()
+	if err := os.Rename(tmp.Name(), d.path(key)); err != nil {
+		_ = os.Remove(tmp.Name())
+		return fmt.Errorf("writing cache entry: %w", err)
+	}
+	return d.evict()
+}
+
+// evict removes the least recently used entries until the total size is within
+// maxBytes, along with temporary files abandoned by crashed writers. The caller
+// must hold the lock.
+func (d *diskCache) evict() error {
+	dirEntries, err := os.ReadDir(d.dir)
+	if err != nil {
+		return fmt.Errorf("reading cache directory: %w", err)
+	}
+	type file struct {
+		path    string
+		size    int64
+		modTime time.Time
+	}
+	var (
+		files []file
+		total int64
+	)
+	for _, de := range dirEntries {
+		info, err := de.Info()
+		if err != nil || !info.Mode().IsRegular() {
+			continue
+		}
+		path := filepath.Join(d.dir, de.Name())
+		switch {
+		case strings.HasPrefix(de.Name(), diskCacheTempPrefix):
+			if time.Since(info.ModTime()) > time.Minute {
+				_ = os.Remove(path)
+			}
+		case strings.HasSuffix(de.Name(), diskCacheEntryExt):
+			files = append(files, file{path: path, size: info.Size(), modTime: info.ModTime()})
+			total += info.Size()
+		}
+	}
+	if d.maxBytes == 0 || total <= d.maxBytes {
+		return nil
+	}
+	slices.SortFunc(files, func(a, b file) int { return a.modTime.Compare(b.modTime) })
+	for _, f := range files {
+		if total <= d.maxBytes {
+			break
+		}
+		if err := os.Remove(f.path); err == nil || errors.Is(err, fs.ErrNotExist) {
+			total -= f.size
+		}
+	}
+	return nil
+}
+
+// lock takes the cache directory's lock file, so that only one process writes or
+// evicts at a time.
+func (d *diskCache) lock() (unlock func(), err error) {
+	d.mu.Lock()
+	unlockFile, err := lockFile(filepath.Join(d.dir, diskCacheLockFile))
+	if err != nil {
+		d.mu.Unlock()
+		return nil, fmt.Errorf("locking cache directory: %w", err)
+	}
+	return func() {
+		unlockFile()
+		d.mu.Unlock()
+	}, nil
+}
+
+func readDiskCacheEntry(path string) (diskCacheEntry, error) {
+	b, err := os.ReadFile(path)
+	if err != nil {
+		return diskCacheEntry{}, err
+	}
+	var entry diskCacheEntry
+	if err := json.Unmarshal(b, &entry); err != nil {
+		return diskCacheEntry{}, err
+	}
+	return entry, nil
+}
@@ -0,0 +1,232 @@
+package keywordplanner
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// APIError is a non-200 response from the Google Ads API. When the body is a
+// GoogleAdsFailure, its errors are decoded into Errors; Body always holds the raw
+// response.
+type APIError struct {
+	// HTTPStatus is the HTTP status code of the response.
+	HTTPStatus int
+	// Status is the canonical RPC status, such as PERMISSION_DENIED.
+	Status string
+	// Message is the top-level error message.
+	Message string
+	// RequestID identifies the request for Google Ads API support.
+	RequestID string
+	// Errors are the individual failures reported in the GoogleAdsFailure details.
+	Errors []GoogleAdsError
+	// RetryDelay is how long Google asks callers to wait before retrying, from quota
+	// error details, RetryInfo or the Retry-After header. Zero when not given.
+	RetryDelay time.Duration
+	// Body is the raw response body.
+	Body string
+}
+
+// GoogleAdsError is one error of a GoogleAdsFailure.
+type GoogleAdsError struct {
+	// Category is the error code's enum family, such as authorizationError.
+	Category string
+	// Code is the error code enum value, such as USER_PERMISSION_DENIED.
+	Code string
+	// Message describes the error.
+	Message string
+	// Trigger is the request value that caused the error, if any.
+	Trigger string
+	// Location is the path of the offending request field, such as
+	// keyword_seed.keywords[0].
+	Location string
+}
+
+// Error summarizes the failure as its code, message and request ID. When the body is
+// not a Google API error it is included verbatim instead, so nothing is lost.
+func (e *APIError) Error() string {
+	if len(e.Errors) == 0 && e.Message == "" {
+		return fmt.Sprintf("Google Ads API returned HTTP %d: %s", e.HTTPStatus, e.Body)
+	}
+	var b strings.Builder
+	fmt.Fprintf(&b, "Google Ads API returned HTTP %d", e.HTTPStatus)
+	if code := e.Code(); code != "" {
+		fmt.Fprintf(&b, " %s", code)
+	}
+	fmt.Fprintf(&b, ": %s", e.Detail())
+	if e.RequestID != "" {
+		fmt.Fprintf(&b, " (request ID %s)", e.RequestID)
+	}
+	return b.String()
+}
+
+// Code returns the Google Ads error code of the first error, falling back to the
+// RPC status when the response carried no GoogleAdsFailure.
+func (e *APIError) Code() string {
+	if len(e.Errors) > 0 && e.Errors[0].Code != "" {
+		return e.Errors[0].Code
+	}
+	return e.Status
+}
+
+// Detail returns the most specific message available: the first GoogleAdsError's
+// message, with its trigger and location when present, or else the top-level one.
+func (e *APIError) Detail() string {
+	if len(e.Errors) == 0 {
+		return e.Message
+	}
+	first := e.Errors[0]
+	detail := first.Message
+	if first.Trigger != "" {
+		detail += fmt.Sprintf(" (trigger %q)", first.Trigger)
+	}
+	if first.Location != "" {
+		detail += fmt.Sprintf(" at %s", first.Location)
+	}
+	return detail
+}
+
+// newAPIError builds an APIError from a non-200 response, decoding body as a
+// GoogleAdsFailure when it is one. The request-id and Retry-After headers are used
+// when the body does not say otherwise.
+func newAPIError(status int, header http.Header, body []byte) *APIError {
+	apiErr := &APIError{
+		HTTPStatus: status,
+		RequestID:  header.Get("request-id"),
+		RetryDelay: parseRetryAfter(header.Get("Retry-After")),
+		Body:       string(body),
+	}
+
+	var envelope googleAdsErrorEnvelope
+	if err := json.Unmarshal(body, &envelope); err != nil || envelope.Error == nil {
+		return apiErr
+	}
+	apiErr.Status = envelope.Error.Status
+	apiErr.Message = envelope.Error.Message
+	for _, detail := range envelope.Error.Details {
+		if strings.HasSuffix(detail.Type, ".RetryInfo") {
+			if d := parseProtoDuration(detail.RetryDelay); d > apiErr.RetryDelay {
+				apiErr.RetryDelay = d
+			}
+			continue
+		}
+		if !strings.HasSuffix(detail.Type, ".GoogleAdsFailure") {
+			continue
+		}
+		if detail.RequestID != "" {
+			apiErr.RequestID = detail.RequestID
+		}
+		for _, e := range detail.Errors {
+			if d := parseProtoDuration(e.Details.QuotaErrorDetails.RetryDelay); d > apiErr.RetryDelay {
+				apiErr.RetryDelay = d
+			}
+			category, code := e.ErrorCode.enum()
+			apiErr.Errors = append(apiErr.Errors, GoogleAdsError{
+				Category: category,
+				Code:     code,
+				Message:  e.Message,
+				Trigger:  e.Trigger.String(),
+				Location: e.Location.String(),
+			})
+		}
+	}
+	return apiErr
+}
+
+// --- Google Ads API raw error types ---
+
+type googleAdsErrorEnvelope struct {
+	Error *googleAdsErrorStatus `json:"error"`
+}
+
+type googleAdsErrorStatus struct {
+	Code    int                     `json:"code"`
+	Message string                  `json:"message"`
+	Status  string                  `json:"status"`
+	Details []googleAdsFailureEntry `json:"details"`
+}
+
+type googleAdsFailureEntry struct {
+	Type      string              `json:"@type"`
+	Errors    []googleAdsErrorRaw `json:"errors"`
+	RequestID string              `json:"requestId"`
+	// RetryDelay is set on google.rpc.RetryInfo details.
+	RetryDelay string `json:"retryDelay"`
+}
+
+type googleAdsErrorRaw struct {
+	ErrorCode errorCode     `json:"errorCode"`
+	Message   string        `json:"message"`
+	Trigger   errorTrigger  `json:"trigger"`
+	Location  errorLocation `json:"location"`
+	Details   struct {
+		QuotaErrorDetails struct {
+			RetryDelay string `json:"retryDelay"`
+		} `json:"quotaErrorDetails"`
+	} `json:"details"`
+}
+
+// errorCode is the ErrorCode oneof: a single member naming the enum family, such as
+// {"authorizationError": "USER_PERMISSION_DENIED"}.
+type errorCode map[string]json.RawMessage
+
+func (c errorCode) enum() (category, code string) {
+	for k, v := range c {
+		var s string
+		if json.Unmarshal(v, &s) == nil {
+			return k, s
+		}
+	}
+	return "", ""
+}
+
+// errorTrigger is the Value oneof, such as {"stringValue": "foo"}.
+type errorTrigger map[string]any
+
+func (t errorTrigger) String() string {
+	for _, v := range t {
+		return fmt.Sprint(v)
+	}
+	return ""
+}
+
+type errorLocation struct {
+	FieldPathElements []struct {
+		FieldName string `json:"fieldName"`
+		Index     *int   `json:"index"`
+	} `json:"fieldPathElements"`
+}
+
+func (l errorLocation) String() string {
+	parts := make([]string, 0, len(l.FieldPathElements))
+	for _, el := range l.FieldPathElements {
+		part := el.FieldName
+		if el.Index != nil {
+			part += fmt.Sprintf("[%d]", *el.Index)
+		}
+		parts = append(parts, part)
+	}
+	return strings.Join(parts, ".")
+}
+
+// parseProtoDuration parses a JSON-encoded protobuf Duration such as "30s" or "1.5s".
+func parseProtoDuration(s string) time.Duration {
+	d, err := time.ParseDuration(s)
+	if err != nil || d < 0 {
+		return 0
+	}
+	return d
+}
+
+// parseRetryAfter parses a Retry-After header given in seconds. HTTP dates are not
+// used by Google and are ignored.
+func parseRetryAfter(s string) time.Duration {
+	seconds, err := strconv.Atoi(strings.TrimSpace(s))
+	if err != nil || seconds < 0 {
+		return 0
+	}
+	return time.Duration(seconds) * time.Second
+}
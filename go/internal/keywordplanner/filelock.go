@@ -0,0 +1,91 @@
+package keywordplanner
+
+import (
+	"crypto/rand"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"time"
+)
+
+const (
+	// staleLockAge is how old a lock file must be before it is assumed to be left
+	// over from a crashed process and taken over. Nothing holds a lock for long.
+	staleLockAge = 10 * time.Second
+	// lockTimeout is how long to wait for a lock before giving up.
+	lockTimeout = 5 * time.Second
+	// lockPollInterval is how often a busy lock is retried.
+	lockPollInterval = 10 * time.Millisecond
+)
+
+// lockFile takes the lock file at path, which is created exclusively so that only
+// one process holds it at a time. The file holds a token unique to this holder, and
+// unlock removes it only while it still holds that token. lockFile waits up to
+// lockTimeout for a busy lock, and takes over a lock file older than staleLockAge
+// as abandoned.
+func lockFile(path string) (unlock func(), err error) {
+	token := rand.Text()
+	deadline := time.Now().Add(lockTimeout)
+	for {
+		f, err := os.OpenFile(path, os.O_CREATE|os.O_EXCL|os.O_WRONLY, 0o644)
+		if err == nil {
+			_, writeErr := f.WriteString(token)
+			if closeErr := f.Close(); writeErr == nil {
+				writeErr = closeErr
+			}
+			if writeErr != nil {
+				_ = os.Remove(path)
+				return nil, writeErr
+			}
+			return func() { removeLockFile(path, token) }, nil
+		}
+		if !errors.Is(err, fs.ErrExist) {
+			return nil, err
+		}
+		if removeStaleLockFile(path, token) {
+			continue
+		}
+		if time.Now().After(deadline) {
+			return nil, fmt.Errorf("%s is held by another process", path)
+		}
+		time.Sleep(lockPollInterval)
+	}
+}
+
+// removeLockFile removes the lock file at path if it still holds token, so a lock
+// that was taken over as stale is left to its new holder.
+func removeLockFile(path, token string) {
+	if b, err := os.ReadFile(path); err == nil && string(b) == token {
+		_ = os.Remove(path)
+	}
+}
+
+// removeStaleLockFile removes the lock file at path if it is older than
+// staleLockAge, and reports whether the lock is now free to take. The file is first
+// renamed aside, which only one process can do, and the renamed file is checked to
+// be the stale one. If another process replaced the stale lock in the meantime, the
+// live lock is put back.
+func removeStaleLockFile(path, token string) bool {
+	info, err := os.Stat(path)
+	if err != nil {
+		return errors.Is(err, fs.ErrNotExist)
+	}
+	if time.Since(info.ModTime()) <= staleLockAge {
+		return false
+	}
+	stale, err := os.ReadFile(path)
+	if err != nil {
+		return false
+	}
+	aside := path + "." + token + ".stale"
+	if err := os.Rename(path, aside); err != nil {
+		return false
+	}
+	if b, err := os.ReadFile(aside); err != nil || string(b) != string(stale) {
+		_ = os.Rename(aside, path)
+		return false
+	}
+	_ = os.Remove(aside)
+	return true
+}
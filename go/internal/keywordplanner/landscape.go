@@ -0,0 +1,137 @@
+package keywordplanner
+
+import (
+	"context"
+	"fmt"
+	"slices"
+)
+
+// MaxBidLandscapePoints is the largest number of bids a bid landscape forecasts.
+// Each bid costs one forecast request.
+const MaxBidLandscapePoints = 20
+
+// bidLandscapeConcurrency bounds the forecast requests a bid landscape has in flight.
+const bidLandscapeConcurrency = 4
+
+// BidLandscapeRequest holds the parameters for Client.GetBidLandscape.
+type BidLandscapeRequest struct {
+	// Forecast describes the campaign to forecast. Its MaxCPCMicros is replaced by
+	// each bid in turn; bids set on individual keywords or ad groups stay fixed.
+	Forecast ForecastRequest
+	// BidsMicros are the max CPC bids to forecast. They are deduplicated and sorted
+	// ascending.
+	BidsMicros []int64
+}
+
+// BidLandscapePoint is the campaign forecast at one max CPC bid.
+type BidLandscapePoint struct {
+	MaxCPCMicros     int64   `json:"maxCpcMicros"`
+	Impressions      float64 `json:"impressions"`
+	Clicks           float64 `json:"clicks"`
+	CostMicros       float64 `json:"costMicros"`
+	AverageCPCMicros float64 `json:"averageCpcMicros"`
+	// MarginalCPCMicros is the extra cost per extra click relative to the previous,
+	// lower bid. It is zero for the first point and when clicks do not increase.
+	MarginalCPCMicros float64 `json:"marginalCpcMicros,omitempty"`
+}
+
+// BidLandscapeResponse is the result of Client.GetBidLandscape.
+type BidLandscapeResponse struct {
+	Points       []BidLandscapePoint `json:"points"`
+	StartDate    string              `json:"startDate"`
+	EndDate      string              `json:"endDate"`
+	ForecastDays int                 `json:"forecastDays"`
+	CacheInfo
+}
+
+// LinearBids returns steps bids evenly spaced from minMicros to maxMicros inclusive,
+// rounded to whole micros.
+func LinearBids(minMicros, maxMicros int64, steps int) ([]int64, error) {
+	if minMicros <= 0 || maxMicros < minMicros {
+		return nil, fmt.Errorf("bid range must satisfy 0 < min <= max, got %d..%d", minMicros, maxMicros)
+	}
+	if steps < 2 || steps > MaxBidLandscapePoints {
+		return nil, fmt.Errorf("steps must be between 2 and %d, got %d", MaxBidLandscapePoints, steps)
+	}
+	bids := make([]int64, steps)
+	span := float64(maxMicros - minMicros)
+	for i := range bids {
+		bids[i] = minMicros + int64(span*float64(i)/float64(steps-1)+0.5)
+	}
+	return bids, nil
+}
+
+// GetBidLandscape forecasts req.Forecast once per bid in req.BidsMicros, a few at a
+// time, and returns the campaign totals at each bid in ascending bid order. Only
+// manual CPC bidding is supported.
+func (c *Client) GetBidLandscape(ctx context.Context, req BidLandscapeRequest) (*BidLandscapeResponse, error) {
+	if err := requireManualCPC(req.Forecast.BiddingStrategy); err != nil {
+		return nil, err
+	}
+	bids := slices.Clone(req.BidsMicros)
+	slices.Sort(bids)
+	bids = slices.Compact(bids)
+	if len(bids) == 0 {
+		return nil, fmt.Errorf("at least one bid is required")
+	}
+	if len(bids) > MaxBidLandscapePoints {
+		return nil, fmt.Errorf("at most %d bids can be forecast, got %d", MaxBidLandscapePoints, len(bids))
+	}
+	if bids[0] <= 0 {
+		return nil, fmt.Errorf("bids must be positive, got %d", bids[0])
+	}
+
+	forecasts := make([]*ForecastResponse, len(bids))
+	err := runConcurrently(ctx, len(bids), bidLandscapeConcurrency, func(ctx context.Context, i int) error {
+		forecastReq := req.Forecast
+		forecastReq.MaxCPCMicros = bids[i]
+		forecast, err := c.GetKeywordForecast(ctx, forecastReq)
+		if err != nil {
+			return fmt.Errorf("forecasting at %d micros: %w", bids[i], err)
+		}
+		forecasts[i] = forecast
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	cacheInfos := make([]CacheInfo, len(forecasts))
+	points := make([]BidLandscapePoint, len(bids))
+	for i, f := range forecasts {
+		cacheInfos[i] = f.CacheInfo
+		points[i] = BidLandscapePoint{
+			MaxCPCMicros:     bids[i],
+			Impressions:      f.Campaign.Impressions,
+			Clicks:           f.Campaign.Clicks,
+			CostMicros:       f.Campaign.CostMicros,
+			AverageCPCMicros: f.Campaign.AverageCPCMicros,
+		}
+		if i > 0 {
+			prev := points[i-1]
+			if extraClicks := points[i].Clicks - prev.Clicks; extraClicks > 0 {
+				points[i].MarginalCPCMicros = (points[i].CostMicros - prev.CostMicros) / extraClicks
+			}
+		}
+	}
+	return &BidLandscapeResponse{
+		Points:       points,
+		StartDate:    forecasts[0].StartDate,
+		EndDate:      forecasts[0].EndDate,
+		ForecastDays: forecasts[0].ForecastDays,
+		CacheInfo:    combineCacheInfo(cacheInfos...),
+	}, nil
+}
+
+// requireManualCPC checks that s bids the campaign max CPC, which bid searches vary.
+// The automated strategies ignore it.
+func requireManualCPC(s BiddingStrategy) error {
+	strategy, err := NormalizeBiddingStrategy(s)
+	if err != nil {
+		return err
+	}
+	if strategy.Type != BiddingStrategyManualCPC {
+		return fmt.Errorf("varying the max CPC bid requires %s bidding, got %s", BiddingStrategyManualCPC, strategy.Type)
+	}
+	return nil
+}
@@ -0,0 +1,192 @@
+package keywordplanner
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+)
+
+// SetHistoricalBatchWindow merges historical metrics lookups that start within
+// window of each other, from any caller, into one request of up to
+// MaxHistoricalKeywordsPerRequest keywords, and hands each caller the metrics of its
+// own keywords. Each lookup waits up to window for others to join it. Lookups with a
+// device breakdown or different options are not merged. Zero turns batching off.
+func (c *Client) SetHistoricalBatchWindow(window time.Duration) {
+	if window <= 0 {
+		c.batcher = nil
+		return
+	}
+	c.batcher = &historicalBatcher{client: c, window: window, pending: make(map[string]*historicalBatch)}
+}
+
+// historicalBatcher collects generateKeywordHistoricalMetrics requests into shared
+// batches.
+type historicalBatcher struct {
+	client *Client
+	window time.Duration
+
+	mu sync.Mutex
+	// pending holds the batch still accepting lookups for each endpoint and set of
+	// options.
+	pending map[string]*historicalBatch
+}
+
+// historicalBatch is one merged request and the lookups waiting on it.
+type historicalBatch struct {
+	endpoint string
+	request  generateHistoricalMetricsRequest
+	// seen holds the normalized keywords already in request, which lookups for the
+	// same keyword share.
+	seen    map[string]bool
+	waiters []*batchWaiter
+	timer   *time.Timer
+}
+
+// batchWaiter is one lookup in a batch and, once done is closed, its share of the
+// response.
+type batchWaiter struct {
+	keywords []string
+	done     chan struct{}
+	body     []byte
+	err      error
+}
+
+// send is a sendFunc that adds the request to a batch and returns the response to
+// its keywords once the batch has been sent.
+func (b *historicalBatcher) send(ctx context.Context, endpoint string, reqBytes []byte) ([]byte, error) {
+	var req generateHistoricalMetricsRequest
+	if err := json.Unmarshal(reqBytes, &req); err != nil {
+		return nil, fmt.Errorf("batching request: %w", err)
+	}
+	if len(req.Keywords) >= MaxHistoricalKeywordsPerRequest {
+		return b.client.send(ctx, endpoint, reqBytes)
+	}
+	options := req
+	options.Keywords = nil
+	optionBytes, err := json.Marshal(options)
+	if err != nil {
+		return nil, fmt.Errorf("batching request: %w", err)
+	}
+	key := endpoint + "\x00" + string(optionBytes)
+	waiter := &batchWaiter{keywords: req.Keywords, done: make(chan struct{})}
+
+	b.mu.Lock()
+	batch := b.pending[key]
+	if batch != nil && len(batch.request.Keywords)+len(req.Keywords) > MaxHistoricalKeywordsPerRequest {
+		b.startLocked(key, batch)
+		batch = nil
+	}
+	if batch == nil {
+		batch = &historicalBatch{endpoint: endpoint, request: options, seen: make(map[string]bool)}
+		b.pending[key] = batch
+		batch.timer = time.AfterFunc(b.window, func() {
+			b.mu.Lock()
+			defer b.mu.Unlock()
+			if b.pending[key] == batch {
+				b.startLocked(key, batch)
+			}
+		})
+	}
+	for _, k := range req.Keywords {
+		if normalized := normalizeKeywordText(k); !batch.seen[normalized] {
+			batch.seen[normalized] = true
+			batch.request.Keywords = append(batch.request.Keywords, k)
+		}
+	}
+	batch.waiters = append(batch.waiters, waiter)
+	if len(batch.request.Keywords) == MaxHistoricalKeywordsPerRequest {
+		b.startLocked(key, batch)
+	}
+	b.mu.Unlock()
+
+	select {
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	case <-waiter.done:
+		return waiter.body, waiter.err
+	}
+}
+
+// startLocked closes batch to further lookups and sends it. The caller must hold
+// b.mu.
+func (b *historicalBatcher) startLocked(key string, batch *historicalBatch) {
+	batch.timer.Stop()
+	delete(b.pending, key)
+	go b.run(batch)
+}
+
+// run sends batch and distributes the response among its waiters.
+func (b *historicalBatcher) run(batch *historicalBatch) {
+	bodies, err := b.fetch(batch)
+	for i, w := range batch.waiters {
+		if err != nil {
+			w.err = err
+		} else {
+			w.body = bodies[i]
+		}
+		close(w.done)
+	}
+}
+
+// fetch sends batch and returns each waiter's share of the response. The request
+// belongs to no single caller, so it is not canceled when one of them gives up.
+func (b *historicalBatcher) fetch(batch *historicalBatch) ([][]byte, error) {
+	reqBytes, err := json.Marshal(batch.request)
+	if err != nil {
+		return nil, fmt.Errorf("marshalling request: %w", err)
+	}
+	body, err := b.client.send(context.Background(), batch.endpoint, reqBytes)
+	if err != nil {
+		return nil, err
+	}
+	// A lookup that no other joined gets the response as it is.
+	if len(batch.waiters) == 1 {
+		return [][]byte{body}, nil
+	}
+	var resp generateHistoricalMetricsResponse
+	if err := json.Unmarshal(body, &resp); err != nil {
+		return nil, fmt.Errorf("parsing response: %w", err)
+	}
+	bodies := make([][]byte, len(batch.waiters))
+	for i, w := range batch.waiters {
+		share, err := json.Marshal(generateHistoricalMetricsResponse{Metrics: metricsForKeywords(resp.Metrics, w.keywords)})
+		if err != nil {
+			return nil, fmt.Errorf("marshalling response: %w", err)
+		}
+		bodies[i] = share
+	}
+	return bodies, nil
+}
+
+// metricsForKeywords returns the results, in response order, whose text or close
+// variants match one of keywords. The API may normalize the case and spacing of the
+// keywords it echoes, so the comparison ignores both.
+func metricsForKeywords(results []historicalMetricsResult, keywords []string) []historicalMetricsResult {
+	wanted := make(map[string]bool, len(keywords))
+	for _, k := range keywords {
+		wanted[normalizeKeywordText(k)] = true
+	}
+	matched := []historicalMetricsResult{}
+	for _, r := range results {
+		if wanted[normalizeKeywordText(r.Text)] {
+			matched = append(matched, r)
+			continue
+		}
+		for _, v := range r.CloseVariants {
+			if wanted[normalizeKeywordText(v)] {
+				matched = append(matched, r)
+				break
+			}
+		}
+	}
+	return matched
+}
+
+// normalizeKeywordText lowercases s and collapses its whitespace, for comparing
+// requested keywords with the text the API echoes back.
+func normalizeKeywordText(s string) string {
+	return strings.ToLower(strings.Join(strings.Fields(s), " "))
+}
@@ -1,72 +1,302 @@
 // Package keywordplanner provides types for the Google Ads Keyword Planner API.
 package keywordplanner
 
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
 // KeywordIdea is a keyword suggestion with historical performance metrics.
 type KeywordIdea struct {
-	Text        string  `json:"text"`
-	AvgMonthlySearches int64  `json:"avgMonthlySearches"`
-	Competition string  `json:"competition"`
-	LowTopOfPageBidMicros  int64 `json:"lowTopOfPageBidMicros,omitempty"`
-	HighTopOfPageBidMicros int64 `json:"highTopOfPageBidMicros,omitempty"`
+	Text                   string           `json:"text"`
+	AvgMonthlySearches     int64            `json:"avgMonthlySearches"`
+	Competition            string           `json:"competition"`
+	CompetitionIndex       int32            `json:"competitionIndex"`
+	LowTopOfPageBidMicros  int64            `json:"lowTopOfPageBidMicros,omitempty"`
+	HighTopOfPageBidMicros int64            `json:"highTopOfPageBidMicros,omitempty"`
+	MonthlySearchVolumes   []MonthlyVolume  `json:"monthlySearchVolumes,omitempty"`
+	Concepts               []KeywordConcept `json:"concepts,omitempty"`
+	// SeedChunk is the 1-based position in KeywordIdeasResponse.SeedChunks of the
+	// seed keywords that produced the idea. It is zero when the seeds were not split.
+	SeedChunk int `json:"seedChunk,omitempty"`
+}
+
+// KeywordConcept is a topical concept Google associates with a keyword idea, and the
+// concept group it belongs to. GroupType is BRAND, OTHER_BRANDS or NON_BRAND.
+type KeywordConcept struct {
+	Name      string `json:"name"`
+	GroupName string `json:"groupName"`
+	GroupType string `json:"groupType"`
+}
+
+// ConceptGroup collects the ideas whose concepts fall in the same concept group.
+type ConceptGroup struct {
+	Name               string   `json:"name"`
+	Type               string   `json:"type"`
+	Keywords           []string `json:"keywords"`
+	Count              int      `json:"count"`
+	AvgMonthlySearches int64    `json:"avgMonthlySearches"`
+}
+
+// KeywordIdeasRequest describes a keyword idea generation call. At least one of
+// SeedKeywords or URL must be set, or else Site on its own.
+type KeywordIdeasRequest struct {
+	SeedKeywords []string
+	URL          string
+	// Site is a domain (e.g. "example.com") whose pages seed the ideas. It cannot
+	// be combined with SeedKeywords or URL.
+	Site string
+	// Language is a languageConstants resource name; empty means all languages.
+	Language string
+	// Locations are geoTargetConstants resource names or bare numeric IDs; empty
+	// means all locations.
+	Locations []string
+	// Network is GOOGLE_SEARCH or GOOGLE_SEARCH_AND_PARTNERS; empty means the
+	// latter, which is the API default.
+	Network string
+	// IncludeAdultKeywords includes adult keywords in the results.
+	IncludeAdultKeywords bool
+	// BreakdownByDevice requests search counts split by device. The breakdown is
+	// left out when the seed keywords are split across several requests.
+	BreakdownByDevice bool
+	// IncludeConcepts requests KEYWORD_CONCEPT annotations for each idea.
+	IncludeConcepts bool
+	// PageSize is the number of ideas per page; zero uses the API default.
+	PageSize int
+	// PageToken resumes from the NextPageToken of a previous response.
+	PageToken string
+	// MaxIdeas, when positive, follows page tokens until this many ideas have been
+	// collected or no pages remain. Zero fetches a single page. When the seed
+	// keywords are split across several requests, the cap covers them all.
+	MaxIdeas int
 }
 
 // KeywordIdeasResponse is the result of generating keyword ideas.
 type KeywordIdeasResponse struct {
-	SeedKeywords []string      `json:"seedKeywords,omitempty"`
-	URL          string        `json:"url,omitempty"`
-	Ideas        []KeywordIdea `json:"ideas"`
-	Count        int           `json:"count"`
+	SeedKeywords         []string         `json:"seedKeywords,omitempty"`
+	URL                  string           `json:"url,omitempty"`
+	Site                 string           `json:"site,omitempty"`
+	Locations            []string         `json:"locations,omitempty"`
+	Network              string           `json:"network"`
+	IncludeAdultKeywords bool             `json:"includeAdultKeywords"`
+	Ideas                []KeywordIdea    `json:"ideas"`
+	Count                int              `json:"count"`
+	NextPageToken        string           `json:"nextPageToken,omitempty"`
+	TotalSize            int64            `json:"totalSize,omitempty"`
+	DeviceSearches       []DeviceSearches `json:"deviceSearches,omitempty"`
+	ConceptGroups        []ConceptGroup   `json:"conceptGroups,omitempty"`
+	// SeedChunks lists the chunks the seed keywords were split into, when there were
+	// too many for one request.
+	SeedChunks [][]string `json:"seedChunks,omitempty"`
+	CacheInfo
+}
+
+// DeviceSearches is the search count attributed to one device type (MOBILE,
+// DESKTOP, TABLET, ...). The API reports the device breakdown for the whole
+// result set rather than per keyword.
+type DeviceSearches struct {
+	Device      string `json:"device"`
+	SearchCount int64  `json:"searchCount"`
 }
 
 // KeywordMetrics holds historical search metrics for a single keyword.
 type KeywordMetrics struct {
-	Text               string        `json:"text"`
-	AvgMonthlySearches int64         `json:"avgMonthlySearches"`
-	Competition        string        `json:"competition"`
-	CompetitionIndex   int32         `json:"competitionIndex"`
-	LowTopOfPageBidMicros  int64     `json:"lowTopOfPageBidMicros,omitempty"`
-	HighTopOfPageBidMicros int64     `json:"highTopOfPageBidMicros,omitempty"`
-	MonthlySearchVolumes []MonthlyVolume `json:"monthlySearchVolumes,omitempty"`
+	Text                   string          `json:"text"`
+	AvgMonthlySearches     int64           `json:"avgMonthlySearches"`
+	Competition            string          `json:"competition"`
+	CompetitionIndex       int32           `json:"competitionIndex"`
+	LowTopOfPageBidMicros  int64           `json:"lowTopOfPageBidMicros,omitempty"`
+	HighTopOfPageBidMicros int64           `json:"highTopOfPageBidMicros,omitempty"`
+	AverageCPCMicros       int64           `json:"averageCpcMicros,omitempty"`
+	MonthlySearchVolumes   []MonthlyVolume `json:"monthlySearchVolumes,omitempty"`
+}
+
+// HistoricalMetricsRequest describes a historical metrics lookup.
+type HistoricalMetricsRequest struct {
+	Keywords []string
+	// StartMonth and EndMonth select an inclusive range of months. Both must be
+	// set or both left zero; zero uses the API default of the trailing 12 months.
+	StartMonth YearMonth
+	EndMonth   YearMonth
+	// IncludeAverageCPC requests the average cost per click for each keyword.
+	IncludeAverageCPC bool
+	// BreakdownByDevice requests search counts split by device.
+	BreakdownByDevice bool
+}
+
+// YearMonth identifies a calendar month.
+type YearMonth struct {
+	Year  int
+	Month time.Month
+}
+
+// IsZero reports whether ym is unset.
+func (ym YearMonth) IsZero() bool {
+	return ym.Year == 0 && ym.Month == 0
+}
+
+// String formats ym as "YYYY-MM".
+func (ym YearMonth) String() string {
+	return fmt.Sprintf("%04d-%02d", ym.Year, int(ym.Month))
+}
+
+// index returns a month count that orders YearMonth values chronologically.
+func (ym YearMonth) index() int {
+	return ym.Year*12 + int(ym.Month) - 1
+}
+
+func (ym YearMonth) toAPI() apiYearMonth {
+	return apiYearMonth{Year: strconv.Itoa(ym.Year), Month: strings.ToUpper(ym.Month.String())}
 }
 
 // MonthlyVolume is the search volume for a specific month.
 type MonthlyVolume struct {
-	Year  int32 `json:"year"`
-	Month int32 `json:"month"`
+	Year            int32 `json:"year"`
+	Month           int32 `json:"month"`
 	MonthlySearches int64 `json:"monthlySearches"`
 }
 
 // HistoricalMetricsResponse is the result of a historical metrics lookup.
 type HistoricalMetricsResponse struct {
-	Keywords []KeywordMetrics `json:"keywords"`
-	Count    int              `json:"count"`
+	Keywords       []KeywordMetrics `json:"keywords"`
+	Count          int              `json:"count"`
+	StartMonth     string           `json:"startMonth,omitempty"`
+	EndMonth       string           `json:"endMonth,omitempty"`
+	DeviceSearches []DeviceSearches `json:"deviceSearches,omitempty"`
+	CacheInfo
+}
+
+// ForecastRequest describes a keyword forecast.
+type ForecastRequest struct {
+	// Keywords are forecast in a single ad group named DefaultAdGroupName. Set
+	// either Keywords or AdGroups, not both.
+	Keywords []ForecastKeyword
+	// AdGroups forecasts several ad groups, each with its own keywords, default bid
+	// and negatives.
+	AdGroups []ForecastAdGroup
+	// MatchType is the match type for keywords that do not set their own; empty
+	// means BROAD.
+	MatchType string
+	// MaxCPCMicros is the campaign max CPC bid; zero or negative means 1,000,000.
+	MaxCPCMicros int64
+	// ForecastDays is the forecast window length; zero or negative means 30. Setting
+	// it together with EndDate is an error.
+	ForecastDays int
+	// StartDate is the first day of the forecast; the zero value means today.
+	StartDate time.Time
+	// EndDate is the last day of the forecast; the zero value means ForecastDays
+	// after StartDate.
+	EndDate time.Time
+	// Locations are geoTargetConstants resource names or bare numeric IDs; empty
+	// means all locations.
+	Locations []string
+	// Languages are languageConstants resource names or bare numeric IDs; empty
+	// means all languages.
+	Languages []string
+	// Network is GOOGLE_SEARCH or GOOGLE_SEARCH_AND_PARTNERS; empty means the latter.
+	Network string
+	// NegativeKeywords are excluded at campaign level as broad match negatives.
+	NegativeKeywords []string
+	// BiddingStrategy selects how the forecast campaign bids; the zero value is
+	// manual CPC at MaxCPCMicros.
+	BiddingStrategy BiddingStrategy
+}
+
+// BiddingStrategy is the bidding strategy a forecast campaign uses. Type selects the
+// variant and determines which of the other fields apply.
+type BiddingStrategy struct {
+	// Type is MANUAL_CPC, MAXIMIZE_CLICKS or MAXIMIZE_CONVERSIONS; empty means MANUAL_CPC.
+	Type string `json:"type"`
+	// DailyBudgetMicros is the average daily spend. Optional for MANUAL_CPC and
+	// required for the automated strategies.
+	DailyBudgetMicros int64 `json:"dailyBudgetMicros,omitempty"`
+	// MaxCPCCeilingMicros caps the CPC bid of MAXIMIZE_CLICKS.
+	MaxCPCCeilingMicros int64 `json:"maxCpcCeilingMicros,omitempty"`
+}
+
+// ForecastAdGroup is one ad group of a forecast campaign. Names must be unique within
+// a request; results are keyed by them.
+type ForecastAdGroup struct {
+	Name     string
+	Keywords []ForecastKeyword
+	// MaxCPCMicros is the default bid for the ad group's keywords; zero inherits the
+	// campaign bid.
+	MaxCPCMicros int64
+	// NegativeKeywords are excluded in this ad group as broad match negatives.
+	NegativeKeywords []string
+}
+
+// ForecastKeyword is a keyword to forecast with an optional match type and bid that
+// override the request defaults.
+type ForecastKeyword struct {
+	Text         string
+	MatchType    string
+	MaxCPCMicros int64
 }
 
 // KeywordForecastMetrics holds projected performance for a keyword.
 type KeywordForecastMetrics struct {
-	Text        string  `json:"text"`
-	Impressions float64 `json:"impressions"`
-	Clicks      float64 `json:"clicks"`
-	CostMicros  float64 `json:"costMicros"`
-	CTR         float64 `json:"ctr"`
+	Text      string `json:"text"`
+	MatchType string `json:"matchType,omitempty"`
+	AdGroup   string `json:"adGroup,omitempty"`
+	ForecastMetrics
+}
+
+// ForecastMetrics is the projected performance of a campaign, ad group or keyword
+// over the forecast window.
+type ForecastMetrics struct {
+	Impressions      float64 `json:"impressions"`
+	Clicks           float64 `json:"clicks"`
+	CostMicros       float64 `json:"costMicros"`
+	CTR              float64 `json:"ctr"`
+	AverageCPCMicros float64 `json:"averageCpcMicros"`
+	Conversions      float64 `json:"conversions"`
+	ConversionRate   float64 `json:"conversionRate"`
+	AverageCPAMicros float64 `json:"averageCpaMicros"`
+}
+
+// AdGroupForecastMetrics is the projected performance of one ad group.
+type AdGroupForecastMetrics struct {
+	ForecastMetrics
 }
 
-// ForecastResponse is the result of a keyword forecast request.
+// ForecastResponse is the result of a keyword forecast request. MaxCPCMicros is the
+// manual CPC bid and is omitted for the automated bidding strategies, which set
+// their own bids.
 type ForecastResponse struct {
-	Keywords  []KeywordForecastMetrics `json:"keywords"`
-	ForecastDays int                  `json:"forecastDays"`
-	MaxCPCMicros int64                `json:"maxCpcMicros"`
+	Campaign         ForecastMetrics                   `json:"campaign"`
+	AdGroups         map[string]AdGroupForecastMetrics `json:"adGroups,omitempty"`
+	Keywords         []KeywordForecastMetrics          `json:"keywords"`
+	StartDate        string                            `json:"startDate"`
+	EndDate          string                            `json:"endDate"`
+	ForecastDays     int                               `json:"forecastDays"`
+	MaxCPCMicros     int64                             `json:"maxCpcMicros,omitempty"`
+	Locations        []string                          `json:"locations,omitempty"`
+	Languages        []string                          `json:"languages,omitempty"`
+	Network          string                            `json:"network"`
+	NegativeKeywords []string                          `json:"negativeKeywords,omitempty"`
+	BiddingStrategy  BiddingStrategy                   `json:"biddingStrategy"`
+	CacheInfo
 }
 
 // --- Google Ads API raw request/response types ---
 
 type generateKeywordIdeasRequest struct {
-	CustomerID             string                     `json:"customerId,omitempty"`
-	Language               string                     `json:"language,omitempty"`
-	GeoTargetConstants     []string                   `json:"geoTargetConstants,omitempty"`
-	KeywordSeed            *keywordSeed               `json:"keywordSeed,omitempty"`
-	URLSeed                *urlSeed                   `json:"urlSeed,omitempty"`
-	KeywordAndURLSeed      *keywordAndURLSeed         `json:"keywordAndUrlSeed,omitempty"`
+	CustomerID           string             `json:"customerId,omitempty"`
+	Language             string             `json:"language,omitempty"`
+	GeoTargetConstants   []string           `json:"geoTargetConstants,omitempty"`
+	KeywordSeed          *keywordSeed       `json:"keywordSeed,omitempty"`
+	URLSeed              *urlSeed           `json:"urlSeed,omitempty"`
+	KeywordAndURLSeed    *keywordAndURLSeed `json:"keywordAndUrlSeed,omitempty"`
+	SiteSeed             *siteSeed          `json:"siteSeed,omitempty"`
+	KeywordPlanNetwork   string             `json:"keywordPlanNetwork,omitempty"`
+	IncludeAdultKeywords bool               `json:"includeAdultKeywords"`
+	AggregateMetrics     *aggregateMetrics  `json:"aggregateMetrics,omitempty"`
+	KeywordAnnotation    []string           `json:"keywordAnnotation,omitempty"`
+	PageSize             int                `json:"pageSize,omitempty"`
+	PageToken            string             `json:"pageToken,omitempty"`
 }
 
 type keywordSeed struct {
@@ -82,49 +312,105 @@ type keywordAndURLSeed struct {
 	Keywords []string `json:"keywords"`
 }
 
+type siteSeed struct {
+	Site string `json:"site"`
+}
+
 type generateKeywordIdeasResponse struct {
-	Results []keywordIdeaResult `json:"results"`
+	Results                []keywordIdeaResult     `json:"results"`
+	NextPageToken          string                  `json:"nextPageToken"`
+	TotalSize              string                  `json:"totalSize"`
+	AggregateMetricResults *aggregateMetricResults `json:"aggregateMetricResults"`
+}
+
+type aggregateMetrics struct {
+	AggregateMetricTypes []string `json:"aggregateMetricTypes"`
+}
+
+type aggregateMetricResults struct {
+	DeviceSearches []deviceSearches `json:"deviceSearches"`
+}
+
+type deviceSearches struct {
+	Device      string `json:"device"`
+	SearchCount string `json:"searchCount"`
 }
 
 type keywordIdeaResult struct {
-	Text            string              `json:"text"`
+	Text               string             `json:"text"`
 	KeywordIdeaMetrics keywordIdeaMetrics `json:"keywordIdeaMetrics"`
+	KeywordAnnotations keywordAnnotations `json:"keywordAnnotations"`
+}
+
+type keywordAnnotations struct {
+	Concepts []keywordConcept `json:"concepts"`
+}
+
+type keywordConcept struct {
+	Name         string       `json:"name"`
+	ConceptGroup conceptGroup `json:"conceptGroup"`
+}
+
+type conceptGroup struct {
+	Name string `json:"name"`
+	Type string `json:"type"`
 }
 
 type keywordIdeaMetrics struct {
-	AvgMonthlySearches     string `json:"avgMonthlySearches"`
-	Competition            string `json:"competition"`
-	CompetitionIndex       string `json:"competitionIndex"`
-	LowTopOfPageBidMicros  string `json:"lowTopOfPageBidMicros"`
-	HighTopOfPageBidMicros string `json:"highTopOfPageBidMicros"`
+	AvgMonthlySearches     string                `json:"avgMonthlySearches"`
+	Competition            string                `json:"competition"`
+	CompetitionIndex       string                `json:"competitionIndex"`
+	LowTopOfPageBidMicros  string                `json:"lowTopOfPageBidMicros"`
+	HighTopOfPageBidMicros string                `json:"highTopOfPageBidMicros"`
+	MonthlySearchVolumes   []monthlySearchVolume `json:"monthlySearchVolumes"`
 }
 
 type generateHistoricalMetricsRequest struct {
-	Keywords []string `json:"keywords"`
+	Keywords                 []string                  `json:"keywords"`
+	HistoricalMetricsOptions *historicalMetricsOptions `json:"historicalMetricsOptions,omitempty"`
+	AggregateMetrics         *aggregateMetrics         `json:"aggregateMetrics,omitempty"`
+}
+
+type historicalMetricsOptions struct {
+	YearMonthRange    *yearMonthRange `json:"yearMonthRange,omitempty"`
+	IncludeAverageCpc bool            `json:"includeAverageCpc,omitempty"`
+}
+
+type yearMonthRange struct {
+	Start apiYearMonth `json:"start"`
+	End   apiYearMonth `json:"end"`
+}
+
+type apiYearMonth struct {
+	Year  string `json:"year"`
+	Month string `json:"month"`
 }
 
 type generateHistoricalMetricsResponse struct {
-	Metrics []historicalMetricsResult `json:"metrics"`
+	Metrics                []historicalMetricsResult `json:"metrics"`
+	AggregateMetricResults *aggregateMetricResults   `json:"aggregateMetricResults"`
 }
 
 type historicalMetricsResult struct {
-	Text           string           `json:"text"`
+	Text           string            `json:"text"`
+	CloseVariants  []string          `json:"closeVariants,omitempty"`
 	KeywordMetrics historicalMetrics `json:"keywordMetrics"`
 }
 
 type historicalMetrics struct {
-	AvgMonthlySearches     string                 `json:"avgMonthlySearches"`
-	Competition            string                 `json:"competition"`
-	CompetitionIndex       int32                  `json:"competitionIndex"`
-	LowTopOfPageBidMicros  string                 `json:"lowTopOfPageBidMicros"`
-	HighTopOfPageBidMicros string                 `json:"highTopOfPageBidMicros"`
-	MonthlySearchVolumes   []monthlySearchVolume  `json:"monthlySearchVolumes"`
+	AvgMonthlySearches     string                `json:"avgMonthlySearches"`
+	Competition            string                `json:"competition"`
+	CompetitionIndex       int32                 `json:"competitionIndex"`
+	LowTopOfPageBidMicros  string                `json:"lowTopOfPageBidMicros"`
+	HighTopOfPageBidMicros string                `json:"highTopOfPageBidMicros"`
+	AverageCpcMicros       string                `json:"averageCpcMicros"`
+	MonthlySearchVolumes   []monthlySearchVolume `json:"monthlySearchVolumes"`
 }
 
 type monthlySearchVolume struct {
-	Year            int32  `json:"year"`
-	Month           string `json:"month"`
-	MonthlySearches string `json:"monthlySearches"`
+	Year            flexInt `json:"year"`
+	Month           string  `json:"month"`
+	MonthlySearches string  `json:"monthlySearches"`
 }
 
 type generateForecastMetricsRequest struct {
@@ -132,26 +418,50 @@ type generateForecastMetricsRequest struct {
 }
 
 type campaignForecastSpec struct {
-	BiddingStrategy biddingStrategy    `json:"biddingStrategy"`
-	StartDate       string             `json:"startDate"`
-	EndDate         string             `json:"endDate"`
-	AdGroups        []adGroupForecast  `json:"adGroups"`
+	BiddingStrategy    biddingStrategy   `json:"biddingStrategy"`
+	StartDate          string            `json:"startDate"`
+	EndDate            string            `json:"endDate"`
+	GeoModifiers       []geoModifier     `json:"geoModifiers,omitempty"`
+	LanguageConstants  []string          `json:"languageConstants,omitempty"`
+	KeywordPlanNetwork string            `json:"keywordPlanNetwork,omitempty"`
+	NegativeKeywords   []forecastKeyword `json:"negativeKeywords,omitempty"`
+	AdGroups           []adGroupForecast `json:"adGroups"`
+}
+
+type geoModifier struct {
+	GeoTargetConstant string `json:"geoTargetConstant"`
 }
 
+// biddingStrategy is a tagged union: exactly one field is set.
 type biddingStrategy struct {
-	ManualCpcBiddingStrategy manualCpcBiddingStrategy `json:"manualCpcBiddingStrategy"`
+	ManualCpcBiddingStrategy           *manualCpcBiddingStrategy           `json:"manualCpcBiddingStrategy,omitempty"`
+	MaximizeClicksBiddingStrategy      *maximizeClicksBiddingStrategy      `json:"maximizeClicksBiddingStrategy,omitempty"`
+	MaximizeConversionsBiddingStrategy *maximizeConversionsBiddingStrategy `json:"maximizeConversionsBiddingStrategy,omitempty"`
 }
 
 type manualCpcBiddingStrategy struct {
-	MaxCPCBidMicros string `json:"maxCpcBidMicros"`
+	DailyBudgetMicros string `json:"dailyBudgetMicros,omitempty"`
+	MaxCPCBidMicros   string `json:"maxCpcBidMicros"`
+}
+
+type maximizeClicksBiddingStrategy struct {
+	DailyTargetSpendMicros string `json:"dailyTargetSpendMicros"`
+	MaxCPCBidCeilingMicros string `json:"maxCpcBidCeilingMicros,omitempty"`
+}
+
+type maximizeConversionsBiddingStrategy struct {
+	DailyTargetSpendMicros string `json:"dailyTargetSpendMicros"`
 }
 
 type adGroupForecast struct {
-	Biddable []adGroupForecastKeyword `json:"biddableKeywords"`
+	MaxCPCBidMicros  string                   `json:"maxCpcBidMicros,omitempty"`
+	Biddable         []adGroupForecastKeyword `json:"biddableKeywords"`
+	NegativeKeywords []forecastKeyword        `json:"negativeKeywords,omitempty"`
 }
 
 type adGroupForecastKeyword struct {
-	Keyword forecastKeyword `json:"keyword"`
+	MaxCPCBidMicros string          `json:"maxCpcBidMicros,omitempty"`
+	Keyword         forecastKeyword `json:"keyword"`
 }
 
 type forecastKeyword struct {
@@ -160,21 +470,76 @@ type forecastKeyword struct {
 }
 
 type generateForecastMetricsResponse struct {
-	AdGroupForecastMetrics []adGroupForecastMetrics `json:"adGroupForecastMetrics"`
+	CampaignForecastMetrics *forecastMetricData      `json:"campaignForecastMetrics"`
+	AdGroupForecastMetrics  []adGroupForecastMetrics `json:"adGroupForecastMetrics"`
 }
 
 type adGroupForecastMetrics struct {
+	Metrics                *forecastMetricData     `json:"metrics"`
 	KeywordForecastMetrics []keywordForecastMetric `json:"keywordForecastMetrics"`
 }
 
 type keywordForecastMetric struct {
-	Keyword  forecastKeyword    `json:"keyword"`
-	Metrics  forecastMetricData `json:"metrics"`
+	Keyword forecastKeyword    `json:"keyword"`
+	Metrics forecastMetricData `json:"metrics"`
 }
 
 type forecastMetricData struct {
-	Impressions float64 `json:"impressions"`
-	Clicks      float64 `json:"clicks"`
-	CostMicros  float64 `json:"costMicros"`
-	CTR         float64 `json:"ctr"`
+	Impressions      flexFloat `json:"impressions"`
+	Clicks           flexFloat `json:"clicks"`
+	CostMicros       flexFloat `json:"costMicros"`
+	CTR              flexFloat `json:"clickThroughRate"`
+	AverageCpcMicros flexFloat `json:"averageCpcMicros"`
+	Conversions      flexFloat `json:"conversions"`
+	ConversionRate   flexFloat `json:"conversionRate"`
+	AverageCpaMicros flexFloat `json:"averageCpaMicros"`
+}
+
+func (m forecastMetricData) toForecastMetrics() ForecastMetrics {
+	return ForecastMetrics{
+		Impressions:      float64(m.Impressions),
+		Clicks:           float64(m.Clicks),
+		CostMicros:       float64(m.CostMicros),
+		CTR:              float64(m.CTR),
+		AverageCPCMicros: float64(m.AverageCpcMicros),
+		Conversions:      float64(m.Conversions),
+		ConversionRate:   float64(m.ConversionRate),
+		AverageCPAMicros: float64(m.AverageCpaMicros),
+	}
+}
+
+// flexFloat decodes a JSON number or a quoted number. The API encodes int64 fields
+// such as costMicros as strings and double fields as numbers.
+type flexFloat float64
+
+func (f *flexFloat) UnmarshalJSON(b []byte) error {
+	s := strings.Trim(string(b), `"`)
+	if s == "" || s == "null" {
+		*f = 0
+		return nil
+	}
+	v, err := strconv.ParseFloat(s, 64)
+	if err != nil {
+		return fmt.Errorf("decoding metric %s: %w", b, err)
+	}
+	*f = flexFloat(v)
+	return nil
+}
+
+// flexInt decodes a JSON integer or a quoted integer, for int64 fields the API may
+// encode either way.
+type flexInt int64
+
+func (n *flexInt) UnmarshalJSON(b []byte) error {
+	s := strings.Trim(string(b), `"`)
+	if s == "" || s == "null" {
+		*n = 0
+		return nil
+	}
+	v, err := strconv.ParseInt(s, 10, 64)
+	if err != nil {
+		return fmt.Errorf("decoding integer %s: %w", b, err)
+	}
+	*n = flexInt(v)
+	return nil
 }
@@ -3,10 +3,21 @@ package keywordplanner_test
 import (
 	"context"
 	"encoding/json"
+	"errors"
+	"fmt"
 	"io"
+	"io/fs"
 	"net/http"
 	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+	"sync/atomic"
 	"testing"
+	"time"
 
 	"github.com/ncosentino/google-keyword-planner-mcp/go/internal/keywordplanner"
 )
@@ -70,7 +81,7 @@ func TestGenerateKeywordIdeas_SendsLoginCustomerIDHeader(t *testing.T) {
 	client := keywordplanner.NewTestClient(
 		"dev-token", "3778350596", "1381404200", srv.URL, srv.Client(),
 	)
-	_, _ = client.GenerateKeywordIdeas(context.Background(), []string{"go"}, "", "")
+	_, _ = client.GenerateKeywordIdeas(context.Background(), keywordplanner.KeywordIdeasRequest{SeedKeywords: []string{"go"}})
 
 	if capturedLoginID != "1381404200" {
 		t.Errorf("login-customer-id header = %q, want %q", capturedLoginID, "1381404200")
@@ -93,7 +104,7 @@ func TestGenerateKeywordIdeas_OmitsLoginCustomerIDHeaderWhenEmpty(t *testing.T)
 	client := keywordplanner.NewTestClient(
 		"dev-token", "3778350596", "", srv.URL, srv.Client(),
 	)
-	_, _ = client.GenerateKeywordIdeas(context.Background(), []string{"go"}, "", "")
+	_, _ = client.GenerateKeywordIdeas(context.Background(), keywordplanner.KeywordIdeasRequest{SeedKeywords: []string{"go"}})
 
 	if capturedLoginID != "" {
 		t.Errorf("login-customer-id header should be absent, got %q", capturedLoginID)
@@ -117,7 +128,10 @@ func TestGetKeywordForecast_ZeroMaxCPCMicros_DefaultsTo1Million(t *testing.T) {
 	defer srv.Close()
 
 	client := keywordplanner.NewTestClient("dev-token", "123", "", srv.URL, srv.Client())
-	_, err := client.GetKeywordForecast(context.Background(), []string{"go"}, 0, 30)
+	_, err := client.GetKeywordForecast(context.Background(), keywordplanner.ForecastRequest{
+		Keywords:     []keywordplanner.ForecastKeyword{{Text: "go"}},
+		ForecastDays: 30,
+	})
 	if err != nil {
 		t.Fatalf("unexpected error: %v", err)
 	}
@@ -147,7 +161,7 @@ func TestPost_ReturnsFullErrorBody(t *testing.T) {
 	defer srv.Close()
 
 	client := keywordplanner.NewTestClient("dev-token", "123", "", srv.URL, srv.Client())
-	_, err := client.GenerateKeywordIdeas(context.Background(), []string{"test"}, "", "")
+	_, err := client.GenerateKeywordIdeas(context.Background(), keywordplanner.KeywordIdeasRequest{SeedKeywords: []string{"test"}})
 	if err == nil {
 		t.Fatal("expected error, got nil")
 	}
@@ -157,3 +171,2749 @@ func TestPost_ReturnsFullErrorBody(t *testing.T) {
 		t.Errorf("error message appears truncated: len=%d, want >= 500", len(errStr))
 	}
 }
+
+// TestGenerateKeywordIdeas_Locations_NormalizedAndEchoed verifies bare numeric IDs
+// and resource names are both sent as geoTargetConstants resource names and that
+// the effective locations are echoed in the response.
+func TestGenerateKeywordIdeas_Locations_NormalizedAndEchoed(t *testing.T) {
+	t.Parallel()
+
+	var captured map[string]any
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_ = json.NewDecoder(r.Body).Decode(&captured)
+		w.Header().Set("Content-Type", "application/json")
+		_ = json.NewEncoder(w).Encode(map[string]any{"results": []any{}})
+	}))
+	defer srv.Close()
+
+	client := keywordplanner.NewTestClient("dev-token", "123", "", srv.URL, srv.Client())
+	resp, err := client.GenerateKeywordIdeas(context.Background(), keywordplanner.KeywordIdeasRequest{
+		SeedKeywords: []string{"go"},
+		Locations:    []string{"2840", "geoTargetConstants/2826"},
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	want := []any{"geoTargetConstants/2840", "geoTargetConstants/2826"}
+	got, _ := captured["geoTargetConstants"].([]any)
+	if len(got) != len(want) || got[0] != want[0] || got[1] != want[1] {
+		t.Errorf("geoTargetConstants = %v, want %v", got, want)
+	}
+	if len(resp.Locations) != 2 || resp.Locations[0] != "geoTargetConstants/2840" {
+		t.Errorf("Locations = %v, want normalized resource names", resp.Locations)
+	}
+}
+
+func TestNormalizeGeoTargetConstants_RejectsInvalid(t *testing.T) {
+	t.Parallel()
+
+	for _, loc := range []string{"", "US", "geoTargetConstants/", "geoTargetConstants/abc", "-5", "languageConstants/1000"} {
+		if _, err := keywordplanner.NormalizeGeoTargetConstants([]string{loc}); err == nil {
+			t.Errorf("NormalizeGeoTargetConstants(%q) returned nil error, want validation error", loc)
+		}
+	}
+}
+
+// TestGenerateKeywordIdeas_SinglePage_SurfacesPaginationFields verifies the page
+// token and size are forwarded and that nextPageToken/totalSize are returned.
+func TestGenerateKeywordIdeas_SinglePage_SurfacesPaginationFields(t *testing.T) {
+	t.Parallel()
+
+	var requests int
+	var captured map[string]any
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		_ = json.NewDecoder(r.Body).Decode(&captured)
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{"results":[{"text":"a"},{"text":"b"}],"nextPageToken":"tok-2","totalSize":"57"}`))
+	}))
+	defer srv.Close()
+
+	client := keywordplanner.NewTestClient("dev-token", "123", "", srv.URL, srv.Client())
+	resp, err := client.GenerateKeywordIdeas(context.Background(), keywordplanner.KeywordIdeasRequest{
+		SeedKeywords: []string{"go"},
+		PageSize:     2,
+		PageToken:    "tok-1",
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if requests != 1 {
+		t.Errorf("requests = %d, want 1 without MaxIdeas", requests)
+	}
+	if captured["pageSize"] != float64(2) || captured["pageToken"] != "tok-1" {
+		t.Errorf("pageSize/pageToken = %v/%v, want 2/tok-1", captured["pageSize"], captured["pageToken"])
+	}
+	if resp.NextPageToken != "tok-2" || resp.TotalSize != 57 || resp.Count != 2 {
+		t.Errorf("response = %+v, want nextPageToken tok-2, totalSize 57, count 2", resp)
+	}
+}
+
+// TestGenerateKeywordIdeas_MaxIdeas_FollowsPagesWithoutOvershooting verifies that
+// MaxIdeas walks page tokens and shrinks the final page to the remaining allowance.
+func TestGenerateKeywordIdeas_MaxIdeas_FollowsPagesWithoutOvershooting(t *testing.T) {
+	t.Parallel()
+
+	var pageSizes []float64
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var body map[string]any
+		_ = json.NewDecoder(r.Body).Decode(&body)
+		size := body["pageSize"].(float64)
+		pageSizes = append(pageSizes, size)
+
+		results := make([]map[string]any, int(size))
+		for i := range results {
+			results[i] = map[string]any{"text": "idea"}
+		}
+		w.Header().Set("Content-Type", "application/json")
+		_ = json.NewEncoder(w).Encode(map[string]any{
+			"results":       results,
+			"nextPageToken": "more",
+			"totalSize":     "100",
+		})
+	}))
+	defer srv.Close()
+
+	client := keywordplanner.NewTestClient("dev-token", "123", "", srv.URL, srv.Client())
+	resp, err := client.GenerateKeywordIdeas(context.Background(), keywordplanner.KeywordIdeasRequest{
+		SeedKeywords: []string{"go"},
+		PageSize:     4,
+		MaxIdeas:     10,
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	want := []float64{4, 4, 2}
+	if len(pageSizes) != len(want) || pageSizes[0] != 4 || pageSizes[1] != 4 || pageSizes[2] != 2 {
+		t.Errorf("requested page sizes = %v, want %v", pageSizes, want)
+	}
+	if resp.Count != 10 {
+		t.Errorf("Count = %d, want 10", resp.Count)
+	}
+	if resp.NextPageToken != "more" {
+		t.Errorf("NextPageToken = %q, want the last page's token", resp.NextPageToken)
+	}
+}
+
+// TestGenerateKeywordIdeas_MaxIdeas_StopsWhenPagesRunOut verifies pagination ends
+// on an empty nextPageToken even if MaxIdeas has not been reached.
+func TestGenerateKeywordIdeas_MaxIdeas_StopsWhenPagesRunOut(t *testing.T) {
+	t.Parallel()
+
+	var requests int
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		requests++
+		w.Header().Set("Content-Type", "application/json")
+		if requests == 1 {
+			_, _ = w.Write([]byte(`{"results":[{"text":"a"}],"nextPageToken":"p2","totalSize":"2"}`))
+			return
+		}
+		_, _ = w.Write([]byte(`{"results":[{"text":"b"}],"totalSize":"2"}`))
+	}))
+	defer srv.Close()
+
+	client := keywordplanner.NewTestClient("dev-token", "123", "", srv.URL, srv.Client())
+	resp, err := client.GenerateKeywordIdeas(context.Background(), keywordplanner.KeywordIdeasRequest{
+		SeedKeywords: []string{"go"},
+		MaxIdeas:     500,
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if requests != 2 || resp.Count != 2 || resp.NextPageToken != "" {
+		t.Errorf("requests=%d count=%d nextPageToken=%q, want 2, 2, empty", requests, resp.Count, resp.NextPageToken)
+	}
+}
+
+// TestGenerateKeywordIdeas_NetworkAndAdult_SentAndEchoed verifies the network and
+// adult-keyword options are sent explicitly and reflected in the response, with the
+// API default network filled in when none is requested.
+func TestGenerateKeywordIdeas_NetworkAndAdult_SentAndEchoed(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name        string
+		network     string
+		adult       bool
+		wantNetwork string
+	}{
+		{name: "default", network: "", adult: false, wantNetwork: "GOOGLE_SEARCH_AND_PARTNERS"},
+		{name: "search only", network: "google_search", adult: true, wantNetwork: "GOOGLE_SEARCH"},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			t.Parallel()
+
+			var captured map[string]any
+			srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				_ = json.NewDecoder(r.Body).Decode(&captured)
+				w.Header().Set("Content-Type", "application/json")
+				_ = json.NewEncoder(w).Encode(map[string]any{"results": []any{}})
+			}))
+			defer srv.Close()
+
+			client := keywordplanner.NewTestClient("dev-token", "123", "", srv.URL, srv.Client())
+			resp, err := client.GenerateKeywordIdeas(context.Background(), keywordplanner.KeywordIdeasRequest{
+				SeedKeywords:         []string{"go"},
+				Network:              test.network,
+				IncludeAdultKeywords: test.adult,
+			})
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if captured["keywordPlanNetwork"] != test.wantNetwork {
+				t.Errorf("keywordPlanNetwork = %v, want %q", captured["keywordPlanNetwork"], test.wantNetwork)
+			}
+			if captured["includeAdultKeywords"] != test.adult {
+				t.Errorf("includeAdultKeywords = %v, want %v", captured["includeAdultKeywords"], test.adult)
+			}
+			if resp.Network != test.wantNetwork || resp.IncludeAdultKeywords != test.adult {
+				t.Errorf("response network/adult = %q/%v, want %q/%v",
+					resp.Network, resp.IncludeAdultKeywords, test.wantNetwork, test.adult)
+			}
+		})
+	}
+}
+
+func TestNormalizeNetwork_RejectsUnknown(t *testing.T) {
+	t.Parallel()
+
+	if _, err := keywordplanner.NormalizeNetwork("DISPLAY"); err == nil {
+		t.Error("NormalizeNetwork(\"DISPLAY\") returned nil error, want validation error")
+	}
+}
+
+// TestGetHistoricalMetrics_Options_SentAndDecoded verifies the year-month range
+// and average CPC options are sent as historicalMetricsOptions and that
+// averageCpcMicros is decoded onto KeywordMetrics.
+func TestGetHistoricalMetrics_Options_SentAndDecoded(t *testing.T) {
+	t.Parallel()
+
+	var captured map[string]any
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_ = json.NewDecoder(r.Body).Decode(&captured)
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{"metrics":[{"text":"go","keywordMetrics":{"avgMonthlySearches":"10","averageCpcMicros":"1250000"}}]}`))
+	}))
+	defer srv.Close()
+
+	start := time.Now().UTC().AddDate(0, -6, 0)
+	end := time.Now().UTC().AddDate(0, -1, 0)
+	client := keywordplanner.NewTestClient("dev-token", "123", "", srv.URL, srv.Client())
+	resp, err := client.GetHistoricalMetrics(context.Background(), keywordplanner.HistoricalMetricsRequest{
+		Keywords:          []string{"go"},
+		StartMonth:        keywordplanner.YearMonth{Year: start.Year(), Month: start.Month()},
+		EndMonth:          keywordplanner.YearMonth{Year: end.Year(), Month: end.Month()},
+		IncludeAverageCPC: true,
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	opts, ok := captured["historicalMetricsOptions"].(map[string]any)
+	if !ok {
+		t.Fatalf("historicalMetricsOptions missing from request: %v", captured)
+	}
+	if opts["includeAverageCpc"] != true {
+		t.Errorf("includeAverageCpc = %v, want true", opts["includeAverageCpc"])
+	}
+	rangeStart := opts["yearMonthRange"].(map[string]any)["start"].(map[string]any)
+	if rangeStart["month"] != strings.ToUpper(start.Month().String()) {
+		t.Errorf("yearMonthRange.start.month = %v, want %s", rangeStart["month"], strings.ToUpper(start.Month().String()))
+	}
+	if resp.Keywords[0].AverageCPCMicros != 1_250_000 {
+		t.Errorf("AverageCPCMicros = %d, want 1250000", resp.Keywords[0].AverageCPCMicros)
+	}
+	if resp.StartMonth != start.Format("2006-01") {
+		t.Errorf("StartMonth = %q, want %q", resp.StartMonth, start.Format("2006-01"))
+	}
+}
+
+// TestGetHistoricalMetrics_NoOptions_OmitsOptionsBlock verifies the request body is
+// unchanged for callers that do not ask for a range or average CPC.
+func TestGetHistoricalMetrics_NoOptions_OmitsOptionsBlock(t *testing.T) {
+	t.Parallel()
+
+	var captured map[string]any
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_ = json.NewDecoder(r.Body).Decode(&captured)
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{"metrics":[]}`))
+	}))
+	defer srv.Close()
+
+	client := keywordplanner.NewTestClient("dev-token", "123", "", srv.URL, srv.Client())
+	if _, err := client.GetHistoricalMetrics(context.Background(), keywordplanner.HistoricalMetricsRequest{Keywords: []string{"go"}}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if _, present := captured["historicalMetricsOptions"]; present {
+		t.Errorf("historicalMetricsOptions should be omitted, got %v", captured["historicalMetricsOptions"])
+	}
+}
+
+func TestGetHistoricalMetrics_InvalidRange_ReturnsError(t *testing.T) {
+	t.Parallel()
+
+	now := time.Now().UTC()
+	month := func(t time.Time) keywordplanner.YearMonth {
+		return keywordplanner.YearMonth{Year: t.Year(), Month: t.Month()}
+	}
+	tests := []struct {
+		name       string
+		start, end keywordplanner.YearMonth
+	}{
+		{name: "end before start", start: month(now.AddDate(0, -2, 0)), end: month(now.AddDate(0, -5, 0))},
+		{name: "end in future", start: month(now.AddDate(0, -2, 0)), end: month(now.AddDate(0, 2, 0))},
+		{name: "older than four years", start: month(now.AddDate(-5, 0, 0)), end: month(now.AddDate(0, -1, 0))},
+		{name: "start only", start: month(now.AddDate(0, -2, 0))},
+	}
+
+	client := keywordplanner.NewTestClient("dev-token", "123", "", "http://127.0.0.1:0", http.DefaultClient)
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			t.Parallel()
+			_, err := client.GetHistoricalMetrics(context.Background(), keywordplanner.HistoricalMetricsRequest{
+				Keywords:   []string{"go"},
+				StartMonth: test.start,
+				EndMonth:   test.end,
+			})
+			if err == nil {
+				t.Error("expected validation error, got nil")
+			}
+		})
+	}
+}
+
+func TestParseYearMonth(t *testing.T) {
+	t.Parallel()
+
+	ym, err := keywordplanner.ParseYearMonth("2024-03")
+	if err != nil || ym.Year != 2024 || ym.Month != time.March {
+		t.Errorf("ParseYearMonth(\"2024-03\") = %+v, %v; want 2024-03", ym, err)
+	}
+	if _, err := keywordplanner.ParseYearMonth("March 2024"); err == nil {
+		t.Error("ParseYearMonth(\"March 2024\") returned nil error")
+	}
+}
+
+// TestGenerateKeywordIdeas_BreakdownByDevice verifies the DEVICE aggregate metric is
+// requested and the per-device search counts are returned.
+func TestGenerateKeywordIdeas_BreakdownByDevice(t *testing.T) {
+	t.Parallel()
+
+	var captured map[string]any
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_ = json.NewDecoder(r.Body).Decode(&captured)
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{
+			"results": [{"text": "go"}],
+			"aggregateMetricResults": {"deviceSearches": [
+				{"device": "MOBILE", "searchCount": "700"},
+				{"device": "DESKTOP", "searchCount": "300"}
+			]}
+		}`))
+	}))
+	defer srv.Close()
+
+	client := keywordplanner.NewTestClient("dev-token", "123", "", srv.URL, srv.Client())
+	resp, err := client.GenerateKeywordIdeas(context.Background(), keywordplanner.KeywordIdeasRequest{
+		SeedKeywords:      []string{"go"},
+		BreakdownByDevice: true,
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	types := captured["aggregateMetrics"].(map[string]any)["aggregateMetricTypes"].([]any)
+	if len(types) != 1 || types[0] != "DEVICE" {
+		t.Errorf("aggregateMetricTypes = %v, want [DEVICE]", types)
+	}
+	if len(resp.DeviceSearches) != 2 || resp.DeviceSearches[0].Device != "MOBILE" || resp.DeviceSearches[0].SearchCount != 700 {
+		t.Errorf("DeviceSearches = %+v, want MOBILE=700, DESKTOP=300", resp.DeviceSearches)
+	}
+}
+
+// TestGetHistoricalMetrics_BreakdownByDevice mirrors the idea-generation test for
+// the historical metrics endpoint, and checks the block is omitted by default.
+func TestGetHistoricalMetrics_BreakdownByDevice(t *testing.T) {
+	t.Parallel()
+
+	var bodies []map[string]any
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var body map[string]any
+		_ = json.NewDecoder(r.Body).Decode(&body)
+		bodies = append(bodies, body)
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{"metrics": [], "aggregateMetricResults": {"deviceSearches": [{"device": "TABLET", "searchCount": "42"}]}}`))
+	}))
+	defer srv.Close()
+
+	client := keywordplanner.NewTestClient("dev-token", "123", "", srv.URL, srv.Client())
+	if _, err := client.GetHistoricalMetrics(context.Background(), keywordplanner.HistoricalMetricsRequest{Keywords: []string{"go"}}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	resp, err := client.GetHistoricalMetrics(context.Background(), keywordplanner.HistoricalMetricsRequest{
+		Keywords:          []string{"go"},
+		BreakdownByDevice: true,
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if _, present := bodies[0]["aggregateMetrics"]; present {
+		t.Error("aggregateMetrics must be omitted when no breakdown is requested")
+	}
+	if _, present := bodies[1]["aggregateMetrics"]; !present {
+		t.Error("aggregateMetrics must be sent when breakdown_by_device is set")
+	}
+	if len(resp.DeviceSearches) != 1 || resp.DeviceSearches[0].SearchCount != 42 {
+		t.Errorf("DeviceSearches = %+v, want TABLET=42", resp.DeviceSearches)
+	}
+}
+
+// TestGenerateKeywordIdeas_DecodesCompetitionIndexAndMonthlyVolumes verifies each
+// idea carries its competition index and monthly search trend, so callers do not
+// need a follow-up historical metrics lookup. The year is an int64 field, which the
+// API may send as a number or a string.
+func TestGenerateKeywordIdeas_DecodesCompetitionIndexAndMonthlyVolumes(t *testing.T) {
+	t.Parallel()
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{"results": [{
+			"text": "blazor",
+			"keywordIdeaMetrics": {
+				"avgMonthlySearches": "1200",
+				"competition": "LOW",
+				"competitionIndex": "17",
+				"monthlySearchVolumes": [
+					{"year": 2025, "month": "NOVEMBER", "monthlySearches": "1100"},
+					{"year": "2025", "month": "DECEMBER", "monthlySearches": "1300"}
+				]
+			}
+		}]}`))
+	}))
+	defer srv.Close()
+
+	client := keywordplanner.NewTestClient("dev-token", "123", "", srv.URL, srv.Client())
+	resp, err := client.GenerateKeywordIdeas(context.Background(), keywordplanner.KeywordIdeasRequest{SeedKeywords: []string{"blazor"}})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	idea := resp.Ideas[0]
+	if idea.CompetitionIndex != 17 {
+		t.Errorf("CompetitionIndex = %d, want 17", idea.CompetitionIndex)
+	}
+	want := []keywordplanner.MonthlyVolume{
+		{Year: 2025, Month: 11, MonthlySearches: 1100},
+		{Year: 2025, Month: 12, MonthlySearches: 1300},
+	}
+	if len(idea.MonthlySearchVolumes) != len(want) {
+		t.Fatalf("MonthlySearchVolumes = %+v, want %+v", idea.MonthlySearchVolumes, want)
+	}
+	for i := range want {
+		if idea.MonthlySearchVolumes[i] != want[i] {
+			t.Errorf("MonthlySearchVolumes[%d] = %+v, want %+v", i, idea.MonthlySearchVolumes[i], want[i])
+		}
+	}
+}
+
+// TestGenerateKeywordIdeas_IncludeConcepts verifies the KEYWORD_CONCEPT annotation
+// is requested and decoded onto each idea.
+func TestGenerateKeywordIdeas_IncludeConcepts(t *testing.T) {
+	t.Parallel()
+
+	var captured map[string]any
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_ = json.NewDecoder(r.Body).Decode(&captured)
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{"results": [{
+			"text": "nike running shoes",
+			"keywordAnnotations": {"concepts": [
+				{"name": "nike", "conceptGroup": {"name": "Brand", "type": "BRAND"}},
+				{"name": "running", "conceptGroup": {"name": "Sport", "type": "NON_BRAND"}}
+			]}
+		}]}`))
+	}))
+	defer srv.Close()
+
+	client := keywordplanner.NewTestClient("dev-token", "123", "", srv.URL, srv.Client())
+	resp, err := client.GenerateKeywordIdeas(context.Background(), keywordplanner.KeywordIdeasRequest{
+		SeedKeywords:    []string{"running shoes"},
+		IncludeConcepts: true,
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	annotations, _ := captured["keywordAnnotation"].([]any)
+	if len(annotations) != 1 || annotations[0] != "KEYWORD_CONCEPT" {
+		t.Errorf("keywordAnnotation = %v, want [KEYWORD_CONCEPT]", captured["keywordAnnotation"])
+	}
+	want := keywordplanner.KeywordConcept{Name: "nike", GroupName: "Brand", GroupType: "BRAND"}
+	if concepts := resp.Ideas[0].Concepts; len(concepts) != 2 || concepts[0] != want {
+		t.Errorf("Concepts = %+v, want first concept %+v", concepts, want)
+	}
+}
+
+func TestGroupIdeasByConcept(t *testing.T) {
+	t.Parallel()
+
+	brand := keywordplanner.KeywordConcept{Name: "nike", GroupName: "Brand", GroupType: "BRAND"}
+	sport := keywordplanner.KeywordConcept{Name: "running", GroupName: "Sport", GroupType: "NON_BRAND"}
+	sport2 := keywordplanner.KeywordConcept{Name: "jogging", GroupName: "Sport", GroupType: "NON_BRAND"}
+
+	groups := keywordplanner.GroupIdeasByConcept([]keywordplanner.KeywordIdea{
+		{Text: "nike running shoes", AvgMonthlySearches: 100, Concepts: []keywordplanner.KeywordConcept{brand, sport}},
+		{Text: "running jogging shoes", AvgMonthlySearches: 500, Concepts: []keywordplanner.KeywordConcept{sport, sport2}},
+		{Text: "shoes", AvgMonthlySearches: 9000},
+	})
+
+	if len(groups) != 2 {
+		t.Fatalf("groups = %+v, want 2 groups", groups)
+	}
+	if groups[0].Name != "Sport" || groups[0].Count != 2 || groups[0].AvgMonthlySearches != 600 {
+		t.Errorf("groups[0] = %+v, want Sport with 2 keywords and 600 searches", groups[0])
+	}
+	if groups[1].Name != "Brand" || groups[1].Type != "BRAND" || len(groups[1].Keywords) != 1 {
+		t.Errorf("groups[1] = %+v, want Brand with 1 keyword", groups[1])
+	}
+}
+
+// TestGetKeywordForecast_PerKeywordMatchTypesAndBids verifies each keyword is sent
+// with its own match type and bid, falling back to the request defaults, and that
+// the match type is returned with the keyword's metrics.
+func TestGetKeywordForecast_PerKeywordMatchTypesAndBids(t *testing.T) {
+	t.Parallel()
+
+	var captured map[string]any
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_ = json.NewDecoder(r.Body).Decode(&captured)
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{"adGroupForecastMetrics": [{"keywordForecastMetrics": [
+			{"keyword": {"text": "blazor"}, "metrics": {"clicks": 5}},
+			{"keyword": {"text": "blazor tutorial", "matchType": "EXACT"}, "metrics": {"clicks": 2}}
+		]}]}`))
+	}))
+	defer srv.Close()
+
+	client := keywordplanner.NewTestClient("dev-token", "123", "", srv.URL, srv.Client())
+	resp, err := client.GetKeywordForecast(context.Background(), keywordplanner.ForecastRequest{
+		Keywords: []keywordplanner.ForecastKeyword{
+			{Text: "blazor"},
+			{Text: "blazor tutorial", MatchType: "exact", MaxCPCMicros: 2_500_000},
+		},
+		MatchType: "PHRASE",
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	adGroups := captured["campaignForecastSpec"].(map[string]any)["adGroups"].([]any)
+	biddable := adGroups[0].(map[string]any)["biddableKeywords"].([]any)
+	first := biddable[0].(map[string]any)
+	second := biddable[1].(map[string]any)
+	if mt := first["keyword"].(map[string]any)["matchType"]; mt != "PHRASE" {
+		t.Errorf("first keyword matchType = %v, want PHRASE (request default)", mt)
+	}
+	if _, present := first["maxCpcBidMicros"]; present {
+		t.Errorf("first keyword must not carry its own bid, got %v", first["maxCpcBidMicros"])
+	}
+	if mt := second["keyword"].(map[string]any)["matchType"]; mt != "EXACT" {
+		t.Errorf("second keyword matchType = %v, want EXACT", mt)
+	}
+	if bid := second["maxCpcBidMicros"]; bid != "2500000" {
+		t.Errorf("second keyword maxCpcBidMicros = %v, want %q", bid, "2500000")
+	}
+
+	if resp.Keywords[0].MatchType != "PHRASE" || resp.Keywords[1].MatchType != "EXACT" {
+		t.Errorf("response match types = %q/%q, want PHRASE/EXACT", resp.Keywords[0].MatchType, resp.Keywords[1].MatchType)
+	}
+}
+
+func TestNormalizeMatchType_RejectsUnknown(t *testing.T) {
+	t.Parallel()
+
+	if _, err := keywordplanner.NormalizeMatchType("MODIFIED_BROAD"); err == nil {
+		t.Error("NormalizeMatchType(\"MODIFIED_BROAD\") returned nil error, want validation error")
+	}
+}
+
+// TestGetKeywordForecast_TargetingAndNegatives verifies locations, languages,
+// network and negative keywords are sent in campaignForecastSpec and echoed back.
+func TestGetKeywordForecast_TargetingAndNegatives(t *testing.T) {
+	t.Parallel()
+
+	var captured map[string]any
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_ = json.NewDecoder(r.Body).Decode(&captured)
+		w.Header().Set("Content-Type", "application/json")
+		_ = json.NewEncoder(w).Encode(map[string]any{"adGroupForecastMetrics": []any{}})
+	}))
+	defer srv.Close()
+
+	client := keywordplanner.NewTestClient("dev-token", "123", "", srv.URL, srv.Client())
+	resp, err := client.GetKeywordForecast(context.Background(), keywordplanner.ForecastRequest{
+		Keywords:         []keywordplanner.ForecastKeyword{{Text: "go"}},
+		Locations:        []string{"2826"},
+		Languages:        []string{"1000"},
+		Network:          "GOOGLE_SEARCH",
+		NegativeKeywords: []string{"free"},
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	spec := captured["campaignForecastSpec"].(map[string]any)
+	geo := spec["geoModifiers"].([]any)[0].(map[string]any)
+	if geo["geoTargetConstant"] != "geoTargetConstants/2826" {
+		t.Errorf("geoModifiers[0] = %v, want geoTargetConstants/2826", geo)
+	}
+	if langs := spec["languageConstants"].([]any); langs[0] != "languageConstants/1000" {
+		t.Errorf("languageConstants = %v, want [languageConstants/1000]", langs)
+	}
+	if spec["keywordPlanNetwork"] != "GOOGLE_SEARCH" {
+		t.Errorf("keywordPlanNetwork = %v, want GOOGLE_SEARCH", spec["keywordPlanNetwork"])
+	}
+	negative := spec["negativeKeywords"].([]any)[0].(map[string]any)
+	if negative["text"] != "free" || negative["matchType"] != "BROAD" {
+		t.Errorf("negativeKeywords[0] = %v, want {free BROAD}", negative)
+	}
+	if resp.Network != "GOOGLE_SEARCH" || resp.Locations[0] != "geoTargetConstants/2826" || resp.NegativeKeywords[0] != "free" {
+		t.Errorf("response targeting = %+v, want echoed network, locations and negatives", resp)
+	}
+}
+
+func TestNormalizeLanguageConstants(t *testing.T) {
+	t.Parallel()
+
+	got, err := keywordplanner.NormalizeLanguageConstants([]string{"1000", "languageConstants/1002"})
+	if err != nil || got[0] != "languageConstants/1000" || got[1] != "languageConstants/1002" {
+		t.Errorf("NormalizeLanguageConstants = %v, %v; want resource names", got, err)
+	}
+	if _, err := keywordplanner.NormalizeLanguageConstants([]string{"en"}); err == nil {
+		t.Error("NormalizeLanguageConstants(\"en\") returned nil error, want validation error")
+	}
+}
+
+// TestGetKeywordForecast_BiddingStrategies verifies each strategy is sent as the
+// matching member of the biddingStrategy union and no other, and that the response
+// reports a max CPC bid only for the strategy that uses one.
+func TestGetKeywordForecast_BiddingStrategies(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name       string
+		strategy   keywordplanner.BiddingStrategy
+		wantKey    string
+		wantBody   map[string]any
+		wantMaxCPC int64
+	}{
+		{
+			name:       "manual cpc default",
+			wantKey:    "manualCpcBiddingStrategy",
+			wantBody:   map[string]any{"maxCpcBidMicros": "1000000"},
+			wantMaxCPC: 1_000_000,
+		},
+		{
+			name:     "maximize clicks with ceiling",
+			strategy: keywordplanner.BiddingStrategy{Type: "maximize_clicks", DailyBudgetMicros: 50_000_000, MaxCPCCeilingMicros: 2_000_000},
+			wantKey:  "maximizeClicksBiddingStrategy",
+			wantBody: map[string]any{"dailyTargetSpendMicros": "50000000", "maxCpcBidCeilingMicros": "2000000"},
+		},
+		{
+			name:     "maximize conversions",
+			strategy: keywordplanner.BiddingStrategy{Type: "MAXIMIZE_CONVERSIONS", DailyBudgetMicros: 80_000_000},
+			wantKey:  "maximizeConversionsBiddingStrategy",
+			wantBody: map[string]any{"dailyTargetSpendMicros": "80000000"},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			t.Parallel()
+
+			var captured map[string]any
+			srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				_ = json.NewDecoder(r.Body).Decode(&captured)
+				w.Header().Set("Content-Type", "application/json")
+				_ = json.NewEncoder(w).Encode(map[string]any{"adGroupForecastMetrics": []any{}})
+			}))
+			defer srv.Close()
+
+			client := keywordplanner.NewTestClient("dev-token", "123", "", srv.URL, srv.Client())
+			resp, err := client.GetKeywordForecast(context.Background(), keywordplanner.ForecastRequest{
+				Keywords:        []keywordplanner.ForecastKeyword{{Text: "go"}},
+				BiddingStrategy: test.strategy,
+			})
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if resp.MaxCPCMicros != test.wantMaxCPC {
+				t.Errorf("MaxCPCMicros = %d, want %d", resp.MaxCPCMicros, test.wantMaxCPC)
+			}
+
+			union := captured["campaignForecastSpec"].(map[string]any)["biddingStrategy"].(map[string]any)
+			if len(union) != 1 {
+				t.Errorf("biddingStrategy = %v, want exactly one member", union)
+			}
+			body, ok := union[test.wantKey].(map[string]any)
+			if !ok {
+				t.Fatalf("biddingStrategy = %v, want member %q", union, test.wantKey)
+			}
+			for k, want := range test.wantBody {
+				if body[k] != want {
+					t.Errorf("%s.%s = %v, want %v", test.wantKey, k, body[k], want)
+				}
+			}
+		})
+	}
+}
+
+func TestNormalizeBiddingStrategy_RejectsInvalid(t *testing.T) {
+	t.Parallel()
+
+	for _, s := range []keywordplanner.BiddingStrategy{
+		{Type: "TARGET_ROAS"},
+		{Type: "MAXIMIZE_CLICKS"},
+		{Type: "MAXIMIZE_CONVERSIONS", DailyBudgetMicros: 1, MaxCPCCeilingMicros: 1},
+		{Type: "MANUAL_CPC", MaxCPCCeilingMicros: 1},
+		{Type: "MANUAL_CPC", DailyBudgetMicros: -1},
+	} {
+		if _, err := keywordplanner.NormalizeBiddingStrategy(s); err == nil {
+			t.Errorf("NormalizeBiddingStrategy(%+v) returned nil error, want validation error", s)
+		}
+	}
+}
+
+// TestGetKeywordForecast_DecodesCampaignAndConversionMetrics verifies the campaign
+// totals and conversion metrics are decoded, including int64 fields the API sends
+// as quoted strings.
+func TestGetKeywordForecast_DecodesCampaignAndConversionMetrics(t *testing.T) {
+	t.Parallel()
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{
+			"campaignForecastMetrics": {
+				"impressions": 2000, "clicks": 100, "costMicros": "150000000", "clickThroughRate": 0.05,
+				"averageCpcMicros": "1500000", "conversions": 4, "conversionRate": 0.04, "averageCpaMicros": "37500000"
+			},
+			"adGroupForecastMetrics": [{"keywordForecastMetrics": [
+				{"keyword": {"text": "go"}, "metrics": {"impressions": 2000, "clicks": 100, "costMicros": "150000000", "conversions": 4}}
+			]}]
+		}`))
+	}))
+	defer srv.Close()
+
+	client := keywordplanner.NewTestClient("dev-token", "123", "", srv.URL, srv.Client())
+	resp, err := client.GetKeywordForecast(context.Background(), keywordplanner.ForecastRequest{
+		Keywords: []keywordplanner.ForecastKeyword{{Text: "go"}},
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	want := keywordplanner.ForecastMetrics{
+		Impressions: 2000, Clicks: 100, CostMicros: 150_000_000, CTR: 0.05,
+		AverageCPCMicros: 1_500_000, Conversions: 4, ConversionRate: 0.04, AverageCPAMicros: 37_500_000,
+	}
+	if resp.Campaign != want {
+		t.Errorf("Campaign = %+v, want %+v", resp.Campaign, want)
+	}
+	if resp.Keywords[0].CostMicros != 150_000_000 || resp.Keywords[0].Conversions != 4 {
+		t.Errorf("Keywords[0] = %+v, want cost 150000000 and 4 conversions", resp.Keywords[0])
+	}
+}
+
+// TestGetKeywordForecast_NoCampaignMetrics_SumsKeywords verifies campaign and ad
+// group totals are derived from keyword metrics when the API omits them.
+func TestGetKeywordForecast_NoCampaignMetrics_SumsKeywords(t *testing.T) {
+	t.Parallel()
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{"adGroupForecastMetrics": [{"keywordForecastMetrics": [
+			{"keyword": {"text": "a"}, "metrics": {"impressions": 1000, "clicks": 30, "costMicros": 30000000, "conversions": 1}},
+			{"keyword": {"text": "b"}, "metrics": {"impressions": 1000, "clicks": 10, "costMicros": 10000000, "conversions": 1}}
+		]}]}`))
+	}))
+	defer srv.Close()
+
+	client := keywordplanner.NewTestClient("dev-token", "123", "", srv.URL, srv.Client())
+	resp, err := client.GetKeywordForecast(context.Background(), keywordplanner.ForecastRequest{
+		Keywords: []keywordplanner.ForecastKeyword{{Text: "a"}, {Text: "b"}},
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	c := resp.Campaign
+	if c.Impressions != 2000 || c.Clicks != 40 || c.CostMicros != 40_000_000 || c.Conversions != 2 {
+		t.Errorf("Campaign totals = %+v, want 2000 impressions, 40 clicks, 40000000 cost, 2 conversions", c)
+	}
+	if c.CTR != 0.02 || c.AverageCPCMicros != 1_000_000 || c.ConversionRate != 0.05 || c.AverageCPAMicros != 20_000_000 {
+		t.Errorf("Campaign derived metrics = %+v, want ctr 0.02, avg cpc 1000000, conv rate 0.05, cpa 20000000", c)
+	}
+	if len(resp.AdGroups) != 1 || resp.AdGroups[keywordplanner.DefaultAdGroupName].Clicks != 40 {
+		t.Errorf("AdGroups = %+v, want one ad group with 40 clicks", resp.AdGroups)
+	}
+}
+
+// TestGetKeywordForecast_MultipleAdGroups verifies each ad group is sent with its
+// own bid and negatives and that metrics come back keyed by ad group name.
+func TestGetKeywordForecast_MultipleAdGroups(t *testing.T) {
+	t.Parallel()
+
+	var captured map[string]any
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_ = json.NewDecoder(r.Body).Decode(&captured)
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{"adGroupForecastMetrics": [
+			{"metrics": {"clicks": 12}, "keywordForecastMetrics": [{"keyword": {"text": "blazor"}, "metrics": {"clicks": 12}}]},
+			{"metrics": {"clicks": 7}, "keywordForecastMetrics": [{"keyword": {"text": "maui"}, "metrics": {"clicks": 7}}]}
+		]}`))
+	}))
+	defer srv.Close()
+
+	client := keywordplanner.NewTestClient("dev-token", "123", "", srv.URL, srv.Client())
+	resp, err := client.GetKeywordForecast(context.Background(), keywordplanner.ForecastRequest{
+		AdGroups: []keywordplanner.ForecastAdGroup{
+			{Name: "web", Keywords: []keywordplanner.ForecastKeyword{{Text: "blazor"}}, MaxCPCMicros: 3_000_000, NegativeKeywords: []string{"jobs"}},
+			{Name: "mobile", Keywords: []keywordplanner.ForecastKeyword{{Text: "maui"}}},
+		},
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	adGroups := captured["campaignForecastSpec"].(map[string]any)["adGroups"].([]any)
+	if len(adGroups) != 2 {
+		t.Fatalf("sent %d ad groups, want 2", len(adGroups))
+	}
+	web := adGroups[0].(map[string]any)
+	mobile := adGroups[1].(map[string]any)
+	if bid := web["maxCpcBidMicros"]; bid != "3000000" {
+		t.Errorf("web maxCpcBidMicros = %v, want %q", bid, "3000000")
+	}
+	if _, present := mobile["maxCpcBidMicros"]; present {
+		t.Errorf("mobile must inherit the campaign bid, got %v", mobile["maxCpcBidMicros"])
+	}
+	if negatives, _ := web["negativeKeywords"].([]any); len(negatives) != 1 {
+		t.Errorf("web negativeKeywords = %v, want one entry", web["negativeKeywords"])
+	}
+	if _, present := mobile["negativeKeywords"]; present {
+		t.Errorf("mobile must not carry negatives, got %v", mobile["negativeKeywords"])
+	}
+
+	if resp.AdGroups["web"].Clicks != 12 || resp.AdGroups["mobile"].Clicks != 7 {
+		t.Errorf("AdGroups = %+v, want web=12 and mobile=7 clicks", resp.AdGroups)
+	}
+	if resp.Keywords[0].AdGroup != "web" || resp.Keywords[1].AdGroup != "mobile" {
+		t.Errorf("keyword ad groups = %q/%q, want web/mobile", resp.Keywords[0].AdGroup, resp.Keywords[1].AdGroup)
+	}
+}
+
+// TestGetKeywordForecast_DuplicateAdGroupNames_ReturnsError verifies ad group
+// names must be unique since results are keyed by them.
+func TestGetKeywordForecast_DuplicateAdGroupNames_ReturnsError(t *testing.T) {
+	t.Parallel()
+
+	client := keywordplanner.NewTestClient("dev-token", "123", "", "http://127.0.0.1:0", http.DefaultClient)
+	_, err := client.GetKeywordForecast(context.Background(), keywordplanner.ForecastRequest{
+		AdGroups: []keywordplanner.ForecastAdGroup{
+			{Name: "web", Keywords: []keywordplanner.ForecastKeyword{{Text: "blazor"}}},
+			{Name: "web", Keywords: []keywordplanner.ForecastKeyword{{Text: "maui"}}},
+		},
+	})
+	if err == nil || !strings.Contains(err.Error(), "duplicate ad group name") {
+		t.Errorf("err = %v, want duplicate ad group name error", err)
+	}
+}
+
+// TestGetKeywordForecast_BidsWithAutomatedStrategy_ReturnsError verifies keyword and
+// ad group max CPC bids are rejected rather than sent with an automated strategy.
+func TestGetKeywordForecast_BidsWithAutomatedStrategy_ReturnsError(t *testing.T) {
+	t.Parallel()
+
+	strategy := keywordplanner.BiddingStrategy{Type: "MAXIMIZE_CLICKS", DailyBudgetMicros: 50_000_000}
+	cases := map[string]keywordplanner.ForecastRequest{
+		"keyword bid": {
+			Keywords:        []keywordplanner.ForecastKeyword{{Text: "blazor", MaxCPCMicros: 2_000_000}},
+			BiddingStrategy: strategy,
+		},
+		"ad group bid": {
+			AdGroups: []keywordplanner.ForecastAdGroup{
+				{Name: "web", MaxCPCMicros: 2_000_000, Keywords: []keywordplanner.ForecastKeyword{{Text: "blazor"}}},
+			},
+			BiddingStrategy: strategy,
+		},
+		"ad group keyword bid": {
+			AdGroups: []keywordplanner.ForecastAdGroup{
+				{Name: "web", Keywords: []keywordplanner.ForecastKeyword{{Text: "blazor", MaxCPCMicros: 2_000_000}}},
+			},
+			BiddingStrategy: keywordplanner.BiddingStrategy{Type: "MAXIMIZE_CONVERSIONS", DailyBudgetMicros: 50_000_000},
+		},
+	}
+	for name, req := range cases {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			client := keywordplanner.NewTestClient("dev-token", "123", "", "http://127.0.0.1:0", http.DefaultClient)
+			_, err := client.GetKeywordForecast(context.Background(), req)
+			if err == nil || !strings.Contains(err.Error(), "needs MANUAL_CPC bidding") {
+				t.Errorf("err = %v, want a MANUAL_CPC bidding error", err)
+			}
+		})
+	}
+}
+
+// TestGetKeywordForecast_ExplicitDates_UsesInjectedClock verifies explicit start and
+// end dates are sent as the forecast period and validated against the client clock.
+func TestGetKeywordForecast_ExplicitDates_UsesInjectedClock(t *testing.T) {
+	t.Parallel()
+
+	var captured map[string]any
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_ = json.NewDecoder(r.Body).Decode(&captured)
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{"adGroupForecastMetrics": []}`))
+	}))
+	defer srv.Close()
+
+	client := keywordplanner.NewTestClient("dev-token", "123", "", srv.URL, srv.Client())
+	client.SetClock(func() time.Time { return time.Date(2025, time.October, 1, 15, 30, 0, 0, time.UTC) })
+
+	resp, err := client.GetKeywordForecast(context.Background(), keywordplanner.ForecastRequest{
+		Keywords:  []keywordplanner.ForecastKeyword{{Text: "gift ideas"}},
+		StartDate: time.Date(2025, time.November, 24, 0, 0, 0, 0, time.UTC),
+		EndDate:   time.Date(2025, time.December, 24, 0, 0, 0, 0, time.UTC),
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	spec := captured["campaignForecastSpec"].(map[string]any)
+	if spec["startDate"] != "2025-11-24" || spec["endDate"] != "2025-12-24" {
+		t.Errorf("sent period = %v..%v, want 2025-11-24..2025-12-24", spec["startDate"], spec["endDate"])
+	}
+	if resp.StartDate != "2025-11-24" || resp.EndDate != "2025-12-24" || resp.ForecastDays != 30 {
+		t.Errorf("response period = %s..%s (%d days), want 2025-11-24..2025-12-24 (30 days)", resp.StartDate, resp.EndDate, resp.ForecastDays)
+	}
+}
+
+// TestGetKeywordForecast_DefaultPeriod_StartsToday verifies the default window runs
+// forecastDays from the client clock's current day.
+func TestGetKeywordForecast_DefaultPeriod_StartsToday(t *testing.T) {
+	t.Parallel()
+
+	var captured map[string]any
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_ = json.NewDecoder(r.Body).Decode(&captured)
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{"adGroupForecastMetrics": []}`))
+	}))
+	defer srv.Close()
+
+	client := keywordplanner.NewTestClient("dev-token", "123", "", srv.URL, srv.Client())
+	client.SetClock(func() time.Time { return time.Date(2025, time.March, 10, 23, 0, 0, 0, time.UTC) })
+
+	if _, err := client.GetKeywordForecast(context.Background(), keywordplanner.ForecastRequest{
+		Keywords:     []keywordplanner.ForecastKeyword{{Text: "go"}},
+		ForecastDays: 7,
+	}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	spec := captured["campaignForecastSpec"].(map[string]any)
+	if spec["startDate"] != "2025-03-10" || spec["endDate"] != "2025-03-17" {
+		t.Errorf("sent period = %v..%v, want 2025-03-10..2025-03-17", spec["startDate"], spec["endDate"])
+	}
+}
+
+func TestGetKeywordForecast_InvalidPeriod_ReturnsError(t *testing.T) {
+	t.Parallel()
+
+	date := func(year int, month time.Month, day int) time.Time {
+		return time.Date(year, month, day, 0, 0, 0, 0, time.UTC)
+	}
+	tests := []struct {
+		name         string
+		start, end   time.Time
+		forecastDays int
+	}{
+		{name: "start in past", start: date(2025, time.September, 30)},
+		{name: "end before start", start: date(2025, time.November, 1), end: date(2025, time.October, 20)},
+		{name: "end beyond a year", end: date(2026, time.October, 5)},
+		{name: "default end beyond a year", start: date(2026, time.September, 1), forecastDays: 60},
+		{name: "days with end date", end: date(2025, time.October, 20), forecastDays: 10},
+	}
+
+	client := keywordplanner.NewTestClient("dev-token", "123", "", "http://127.0.0.1:0", http.DefaultClient)
+	client.SetClock(func() time.Time { return time.Date(2025, time.October, 1, 0, 0, 0, 0, time.UTC) })
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			t.Parallel()
+			_, err := client.GetKeywordForecast(context.Background(), keywordplanner.ForecastRequest{
+				Keywords:     []keywordplanner.ForecastKeyword{{Text: "go"}},
+				StartDate:    test.start,
+				EndDate:      test.end,
+				ForecastDays: test.forecastDays,
+			})
+			if err == nil {
+				t.Error("GetKeywordForecast returned nil error, want period validation error")
+			}
+		})
+	}
+}
+
+// landscapeServer answers forecast requests with clicks and cost that grow with the
+// manual CPC bid, and records the peak number of requests in flight.
+func landscapeServer(t *testing.T, peak *atomic.Int32) *httptest.Server {
+	t.Helper()
+	var inFlight atomic.Int32
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		n := inFlight.Add(1)
+		defer inFlight.Add(-1)
+		for {
+			p := peak.Load()
+			if n <= p || peak.CompareAndSwap(p, n) {
+				break
+			}
+		}
+		time.Sleep(10 * time.Millisecond)
+
+		var body struct {
+			CampaignForecastSpec struct {
+				BiddingStrategy struct {
+					ManualCpcBiddingStrategy struct {
+						MaxCpcBidMicros string `json:"maxCpcBidMicros"`
+					} `json:"manualCpcBiddingStrategy"`
+				} `json:"biddingStrategy"`
+			} `json:"campaignForecastSpec"`
+		}
+		_ = json.NewDecoder(r.Body).Decode(&body)
+		bid, _ := strconv.ParseInt(body.CampaignForecastSpec.BiddingStrategy.ManualCpcBiddingStrategy.MaxCpcBidMicros, 10, 64)
+		clicks := bid / 10_000
+		cost := clicks * bid / 2
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = fmt.Fprintf(w, `{"campaignForecastMetrics": {"impressions": %d, "clicks": %d, "costMicros": %d}}`, clicks*20, clicks, cost)
+	}))
+}
+
+// TestGetBidLandscape_ForecastsEachBidConcurrently verifies one forecast is made per
+// bid with bounded concurrency, and that points are sorted with marginal CPCs.
+func TestGetBidLandscape_ForecastsEachBidConcurrently(t *testing.T) {
+	t.Parallel()
+
+	var peak atomic.Int32
+	srv := landscapeServer(t, &peak)
+	defer srv.Close()
+
+	bids, err := keywordplanner.LinearBids(500_000, 5_000_000, 10)
+	if err != nil {
+		t.Fatalf("LinearBids: %v", err)
+	}
+	slices.Reverse(bids)
+
+	client := keywordplanner.NewTestClient("dev-token", "123", "", srv.URL, srv.Client())
+	resp, err := client.GetBidLandscape(context.Background(), keywordplanner.BidLandscapeRequest{
+		Forecast:   keywordplanner.ForecastRequest{Keywords: []keywordplanner.ForecastKeyword{{Text: "go"}}},
+		BidsMicros: bids,
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if len(resp.Points) != 10 {
+		t.Fatalf("got %d points, want 10", len(resp.Points))
+	}
+	if resp.Points[0].MaxCPCMicros != 500_000 || resp.Points[9].MaxCPCMicros != 5_000_000 {
+		t.Errorf("bids = %d..%d, want 500000..5000000 ascending", resp.Points[0].MaxCPCMicros, resp.Points[9].MaxCPCMicros)
+	}
+	if resp.Points[0].MarginalCPCMicros != 0 {
+		t.Errorf("first point MarginalCPCMicros = %v, want 0", resp.Points[0].MarginalCPCMicros)
+	}
+	// 500000 -> 1000000 micros: clicks 50 -> 100, cost 12500000 -> 50000000.
+	if got := resp.Points[1].MarginalCPCMicros; got != 750_000 {
+		t.Errorf("second point MarginalCPCMicros = %v, want 750000", got)
+	}
+	if p := peak.Load(); p > 4 {
+		t.Errorf("peak concurrent requests = %d, want at most 4", p)
+	}
+}
+
+// TestGetBidLandscape_ForecastError_IsReturned verifies a failing forecast fails the
+// whole landscape with the bid it was made at.
+func TestGetBidLandscape_ForecastError_IsReturned(t *testing.T) {
+	t.Parallel()
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		http.Error(w, `{"error":{"message":"boom"}}`, http.StatusBadRequest)
+	}))
+	defer srv.Close()
+
+	client := keywordplanner.NewTestClient("dev-token", "123", "", srv.URL, srv.Client())
+	_, err := client.GetBidLandscape(context.Background(), keywordplanner.BidLandscapeRequest{
+		Forecast:   keywordplanner.ForecastRequest{Keywords: []keywordplanner.ForecastKeyword{{Text: "go"}}},
+		BidsMicros: []int64{1_000_000, 2_000_000},
+	})
+	if err == nil || !strings.Contains(err.Error(), "forecasting at") || !strings.Contains(err.Error(), "boom") {
+		t.Errorf("err = %v, want forecast error naming the bid", err)
+	}
+}
+
+func TestGetBidLandscape_AutomatedBidding_ReturnsError(t *testing.T) {
+	t.Parallel()
+
+	client := keywordplanner.NewTestClient("dev-token", "123", "", "http://127.0.0.1:0", http.DefaultClient)
+	_, err := client.GetBidLandscape(context.Background(), keywordplanner.BidLandscapeRequest{
+		Forecast: keywordplanner.ForecastRequest{
+			Keywords:        []keywordplanner.ForecastKeyword{{Text: "go"}},
+			BiddingStrategy: keywordplanner.BiddingStrategy{Type: keywordplanner.BiddingStrategyMaximizeClicks, DailyBudgetMicros: 10_000_000},
+		},
+		BidsMicros: []int64{1_000_000},
+	})
+	if err == nil {
+		t.Error("GetBidLandscape returned nil error, want manual CPC requirement error")
+	}
+}
+
+func TestLinearBids_EvenlySpaced(t *testing.T) {
+	t.Parallel()
+
+	bids, err := keywordplanner.LinearBids(1_000_000, 2_000_000, 3)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !slices.Equal(bids, []int64{1_000_000, 1_500_000, 2_000_000}) {
+		t.Errorf("LinearBids = %v, want [1000000 1500000 2000000]", bids)
+	}
+	if _, err := keywordplanner.LinearBids(1_000_000, 2_000_000, 1); err == nil {
+		t.Error("LinearBids with 1 step returned nil error, want validation error")
+	}
+}
+
+// TestPlanForBudget_BudgetLimited_FindsBidThatFits verifies the bid search settles
+// on the highest bid whose forecast cost stays within the budget.
+func TestPlanForBudget_BudgetLimited_FindsBidThatFits(t *testing.T) {
+	t.Parallel()
+
+	var peak atomic.Int32
+	srv := landscapeServer(t, &peak)
+	defer srv.Close()
+
+	// Cost is bid²/20000 micros, so a 50,000,000 micro budget fits bids up to 1,000,000.
+	client := keywordplanner.NewTestClient("dev-token", "123", "", srv.URL, srv.Client())
+	resp, err := client.PlanForBudget(context.Background(), keywordplanner.BudgetPlanRequest{
+		Forecast:     keywordplanner.ForecastRequest{Keywords: []keywordplanner.ForecastKeyword{{Text: "go"}}},
+		BudgetMicros: 50_000_000,
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if !resp.BudgetLimited {
+		t.Error("BudgetLimited = false, want true")
+	}
+	if resp.MaxCPCMicros > 1_000_000 || resp.MaxCPCMicros < 980_000 {
+		t.Errorf("MaxCPCMicros = %d, want just under 1000000", resp.MaxCPCMicros)
+	}
+	if resp.Campaign.CostMicros > 50_000_000 {
+		t.Errorf("Campaign.CostMicros = %v, want at most the budget", resp.Campaign.CostMicros)
+	}
+}
+
+// TestPlanForBudget_AmpleBudget_UsesMaxBid verifies the top of the bid range is
+// chosen when the budget covers it.
+func TestPlanForBudget_AmpleBudget_UsesMaxBid(t *testing.T) {
+	t.Parallel()
+
+	var peak atomic.Int32
+	srv := landscapeServer(t, &peak)
+	defer srv.Close()
+
+	client := keywordplanner.NewTestClient("dev-token", "123", "", srv.URL, srv.Client())
+	resp, err := client.PlanForBudget(context.Background(), keywordplanner.BudgetPlanRequest{
+		Forecast:     keywordplanner.ForecastRequest{Keywords: []keywordplanner.ForecastKeyword{{Text: "go"}}},
+		BudgetMicros: 1_000_000_000,
+		MaxCPCMicros: 3_000_000,
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if resp.BudgetLimited || resp.MaxCPCMicros != 3_000_000 {
+		t.Errorf("plan = bid %d, budgetLimited %v; want bid 3000000, not budget limited", resp.MaxCPCMicros, resp.BudgetLimited)
+	}
+}
+
+// TestPlanForBudget_BudgetTooSmall_DropsCostliestKeywords verifies keywords with the
+// highest cost per click are dropped when even the lowest bid overspends.
+func TestPlanForBudget_BudgetTooSmall_DropsCostliestKeywords(t *testing.T) {
+	t.Parallel()
+
+	var calls atomic.Int32
+	srv := forecastEchoServer(t, &calls, func(s string) string { return s }, map[string]string{
+		"cheap":  `{"clicks": 10, "costMicros": 1000000}`,
+		"pricey": `{"clicks": 2, "costMicros": 20000000}`,
+	})
+	defer srv.Close()
+
+	client := keywordplanner.NewTestClient("dev-token", "123", "", srv.URL, srv.Client())
+	resp, err := client.PlanForBudget(context.Background(), keywordplanner.BudgetPlanRequest{
+		Forecast: keywordplanner.ForecastRequest{
+			Keywords: []keywordplanner.ForecastKeyword{{Text: "pricey"}, {Text: "cheap"}},
+		},
+		BudgetMicros: 5_000_000,
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if len(resp.DroppedKeywords) != 1 || resp.DroppedKeywords[0].Text != "pricey" {
+		t.Errorf("DroppedKeywords = %+v, want only pricey", resp.DroppedKeywords)
+	}
+	if len(resp.Keywords) != 1 || resp.Keywords[0].Text != "cheap" {
+		t.Errorf("Keywords = %+v, want only cheap", resp.Keywords)
+	}
+}
+
+// forecastEchoServer answers forecasts with a keyword result per requested keyword,
+// its text rewritten by echo and its metrics looked up by the rewritten text. It
+// counts requests in calls.
+func forecastEchoServer(t *testing.T, calls *atomic.Int32, echo func(string) string, metrics map[string]string) *httptest.Server {
+	t.Helper()
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls.Add(1)
+		var body struct {
+			CampaignForecastSpec struct {
+				AdGroups []struct {
+					BiddableKeywords []struct {
+						Keyword struct {
+							Text string `json:"text"`
+						} `json:"keyword"`
+					} `json:"biddableKeywords"`
+				} `json:"adGroups"`
+			} `json:"campaignForecastSpec"`
+		}
+		_ = json.NewDecoder(r.Body).Decode(&body)
+		var keywords []string
+		for _, kw := range body.CampaignForecastSpec.AdGroups[0].BiddableKeywords {
+			text := echo(kw.Keyword.Text)
+			keywords = append(keywords, fmt.Sprintf(`{"keyword": {"text": %q}, "metrics": %s}`, text, metrics[text]))
+		}
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = fmt.Fprintf(w, `{"adGroupForecastMetrics": [{"keywordForecastMetrics": [%s]}]}`, strings.Join(keywords, ","))
+	}))
+}
+
+// TestPlanForBudget_KeywordEchoedInOtherCase_IsDropped verifies a keyword the API
+// echoes in a different case than requested is still removed when dropped.
+func TestPlanForBudget_KeywordEchoedInOtherCase_IsDropped(t *testing.T) {
+	t.Parallel()
+
+	var calls atomic.Int32
+	srv := forecastEchoServer(t, &calls, strings.ToLower, map[string]string{
+		"rust":   `{"clicks": 10, "costMicros": 1000000}`,
+		"golang": `{"clicks": 2, "costMicros": 20000000}`,
+	})
+	defer srv.Close()
+
+	client := keywordplanner.NewTestClient("dev-token", "123", "", srv.URL, srv.Client())
+	resp, err := client.PlanForBudget(context.Background(), keywordplanner.BudgetPlanRequest{
+		Forecast: keywordplanner.ForecastRequest{
+			Keywords: []keywordplanner.ForecastKeyword{{Text: "GoLang"}, {Text: "Rust"}},
+		},
+		BudgetMicros: 5_000_000,
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if len(resp.DroppedKeywords) != 1 || resp.DroppedKeywords[0].Text != "golang" {
+		t.Errorf("DroppedKeywords = %+v, want only golang", resp.DroppedKeywords)
+	}
+	if len(resp.Keywords) != 1 || resp.Keywords[0].Text != "rust" {
+		t.Errorf("Keywords = %+v, want only rust", resp.Keywords)
+	}
+}
+
+// TestPlanForBudget_UnmatchedKeywordEcho_ReturnsError verifies dropping keywords
+// stops with an error, rather than repeating the same forecast, when the API names
+// keywords that match nothing in the request.
+func TestPlanForBudget_UnmatchedKeywordEcho_ReturnsError(t *testing.T) {
+	t.Parallel()
+
+	var calls atomic.Int32
+	srv := forecastEchoServer(t, &calls, func(s string) string { return s + " (broad)" }, map[string]string{
+		"golang (broad)": `{"clicks": 2, "costMicros": 20000000}`,
+		"rust (broad)":   `{"clicks": 10, "costMicros": 1000000}`,
+	})
+	defer srv.Close()
+
+	client := keywordplanner.NewTestClient("dev-token", "123", "", srv.URL, srv.Client())
+	_, err := client.PlanForBudget(context.Background(), keywordplanner.BudgetPlanRequest{
+		Forecast: keywordplanner.ForecastRequest{
+			Keywords: []keywordplanner.ForecastKeyword{{Text: "golang"}, {Text: "rust"}},
+		},
+		BudgetMicros: 5_000_000,
+	})
+	if err == nil {
+		t.Fatal("expected an error, got nil")
+	}
+	if n := calls.Load(); n != 1 {
+		t.Errorf("requests = %d, want 1", n)
+	}
+}
+
+// TestPlanForBudget_CampaignMetricsOnly_ReturnsError verifies an over-budget forecast
+// without per-keyword metrics is reported as such, rather than as a budget that
+// cannot cover any keyword.
+func TestPlanForBudget_CampaignMetricsOnly_ReturnsError(t *testing.T) {
+	t.Parallel()
+
+	var calls atomic.Int32
+	srv := countingServer(t, &calls, func(w http.ResponseWriter) {
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{"campaignForecastMetrics": {"clicks": 50, "costMicros": 90000000}}`))
+	})
+	defer srv.Close()
+
+	client := keywordplanner.NewTestClient("dev-token", "123", "", srv.URL, srv.Client())
+	_, err := client.PlanForBudget(context.Background(), keywordplanner.BudgetPlanRequest{
+		Forecast: keywordplanner.ForecastRequest{
+			Keywords: []keywordplanner.ForecastKeyword{{Text: "golang"}, {Text: "rust"}},
+		},
+		BudgetMicros: 5_000_000,
+	})
+	if err == nil || !strings.Contains(err.Error(), "no per-keyword breakdown") {
+		t.Errorf("err = %v, want a missing per-keyword breakdown error", err)
+	}
+	if n := calls.Load(); n != 1 {
+		t.Errorf("requests = %d, want 1", n)
+	}
+}
+
+func TestPlanForBudget_NonPositiveBudget_ReturnsError(t *testing.T) {
+	t.Parallel()
+
+	client := keywordplanner.NewTestClient("dev-token", "123", "", "http://127.0.0.1:0", http.DefaultClient)
+	_, err := client.PlanForBudget(context.Background(), keywordplanner.BudgetPlanRequest{
+		Forecast: keywordplanner.ForecastRequest{Keywords: []keywordplanner.ForecastKeyword{{Text: "go"}}},
+	})
+	if err == nil {
+		t.Error("PlanForBudget returned nil error, want budget validation error")
+	}
+}
+
+// TestPost_GoogleAdsFailure_ReturnsAPIError verifies a GoogleAdsFailure body is
+// decoded into an APIError reachable with errors.As.
+func TestPost_GoogleAdsFailure_ReturnsAPIError(t *testing.T) {
+	t.Parallel()
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.Header().Set("request-id", "header-id")
+		w.WriteHeader(http.StatusBadRequest)
+		_, _ = w.Write([]byte(`{"error": {"code": 400, "message": "Request contains an invalid argument.", "status": "INVALID_ARGUMENT",
+			"details": [{"@type": "type.googleapis.com/google.ads.googleads.v23.errors.GoogleAdsFailure",
+				"errors": [{"errorCode": {"keywordPlanIdeaError": "URL_CRAWL_ERROR"}, "message": "The URL could not be crawled.",
+					"trigger": {"stringValue": "https://bad.example"},
+					"location": {"fieldPathElements": [{"fieldName": "url_seed"}, {"fieldName": "url"}]}}],
+				"requestId": "body-id"}]}}`))
+	}))
+	defer srv.Close()
+
+	client := keywordplanner.NewTestClient("dev-token", "123", "", srv.URL, srv.Client())
+	_, err := client.GenerateKeywordIdeas(context.Background(), keywordplanner.KeywordIdeasRequest{URL: "https://bad.example"})
+
+	var apiErr *keywordplanner.APIError
+	if !errors.As(err, &apiErr) {
+		t.Fatalf("err = %v, want an *APIError", err)
+	}
+	if apiErr.HTTPStatus != http.StatusBadRequest || apiErr.Status != "INVALID_ARGUMENT" || apiErr.RequestID != "body-id" {
+		t.Errorf("APIError = %+v, want HTTP 400, INVALID_ARGUMENT, request ID body-id", apiErr)
+	}
+	if apiErr.Code() != "URL_CRAWL_ERROR" {
+		t.Errorf("Code() = %q, want URL_CRAWL_ERROR", apiErr.Code())
+	}
+	want := keywordplanner.GoogleAdsError{
+		Category: "keywordPlanIdeaError",
+		Code:     "URL_CRAWL_ERROR",
+		Message:  "The URL could not be crawled.",
+		Trigger:  "https://bad.example",
+		Location: "url_seed.url",
+	}
+	if len(apiErr.Errors) != 1 || apiErr.Errors[0] != want {
+		t.Errorf("Errors = %+v, want [%+v]", apiErr.Errors, want)
+	}
+	if !strings.Contains(err.Error(), "URL_CRAWL_ERROR") || !strings.Contains(err.Error(), "body-id") {
+		t.Errorf("Error() = %q, want it to name the code and request ID", err.Error())
+	}
+}
+
+// countingServer answers each request with the next of responses, repeating the
+// last one, and counts the requests it receives.
+func countingServer(t *testing.T, calls *atomic.Int32, responses ...func(http.ResponseWriter)) *httptest.Server {
+	t.Helper()
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		n := int(calls.Add(1))
+		responses[min(n, len(responses))-1](w)
+	}))
+}
+
+func respondStatus(status int, body string) func(http.ResponseWriter) {
+	return func(w http.ResponseWriter) {
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(status)
+		_, _ = w.Write([]byte(body))
+	}
+}
+
+// TestPost_TransientFailure_IsRetried verifies a 503 is retried and the request
+// succeeds on the next attempt.
+func TestPost_TransientFailure_IsRetried(t *testing.T) {
+	t.Parallel()
+
+	var calls atomic.Int32
+	srv := countingServer(t, &calls,
+		respondStatus(http.StatusServiceUnavailable, `{"error": {"code": 503, "message": "unavailable", "status": "UNAVAILABLE"}}`),
+		respondStatus(http.StatusOK, `{"metrics": []}`),
+	)
+	defer srv.Close()
+
+	client := keywordplanner.NewTestClient("dev-token", "123", "", srv.URL, srv.Client())
+	client.SetRetryPolicy(keywordplanner.RetryPolicy{MaxAttempts: 3, InitialBackoff: time.Millisecond, MaxBackoff: 10 * time.Millisecond})
+
+	if _, err := client.GetHistoricalMetrics(context.Background(), keywordplanner.HistoricalMetricsRequest{Keywords: []string{"go"}}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if n := calls.Load(); n != 2 {
+		t.Errorf("requests = %d, want 2", n)
+	}
+}
+
+// TestPost_ValidationError_IsNotRetried verifies rejected requests fail on the first
+// attempt.
+func TestPost_ValidationError_IsNotRetried(t *testing.T) {
+	t.Parallel()
+
+	var calls atomic.Int32
+	srv := countingServer(t, &calls,
+		respondStatus(http.StatusBadRequest, `{"error": {"code": 400, "message": "bad", "status": "INVALID_ARGUMENT"}}`),
+	)
+	defer srv.Close()
+
+	client := keywordplanner.NewTestClient("dev-token", "123", "", srv.URL, srv.Client())
+	client.SetRetryPolicy(keywordplanner.RetryPolicy{MaxAttempts: 3, InitialBackoff: time.Millisecond})
+
+	if _, err := client.GetHistoricalMetrics(context.Background(), keywordplanner.HistoricalMetricsRequest{Keywords: []string{"go"}}); err == nil {
+		t.Fatal("expected error, got nil")
+	}
+	if n := calls.Load(); n != 1 {
+		t.Errorf("requests = %d, want 1", n)
+	}
+}
+
+// TestPost_QuotaError_HonorsRetryDelay verifies the retry waits at least as long as
+// the retryDelay in the quota error details.
+func TestPost_QuotaError_HonorsRetryDelay(t *testing.T) {
+	t.Parallel()
+
+	var calls atomic.Int32
+	srv := countingServer(t, &calls,
+		respondStatus(http.StatusTooManyRequests, `{"error": {"code": 429, "message": "quota", "status": "RESOURCE_EXHAUSTED",
+			"details": [{"@type": "type.googleapis.com/google.ads.googleads.v23.errors.GoogleAdsFailure",
+				"errors": [{"errorCode": {"quotaError": "RESOURCE_TEMPORARILY_EXHAUSTED"}, "message": "Too many requests.",
+					"details": {"quotaErrorDetails": {"rateScope": "DEVELOPER", "retryDelay": "0.05s"}}}]}]}}`),
+		respondStatus(http.StatusOK, `{"metrics": []}`),
+	)
+	defer srv.Close()
+
+	client := keywordplanner.NewTestClient("dev-token", "123", "", srv.URL, srv.Client())
+	client.SetRetryPolicy(keywordplanner.RetryPolicy{MaxAttempts: 2, InitialBackoff: time.Millisecond, MaxBackoff: time.Second})
+
+	start := time.Now()
+	if _, err := client.GetHistoricalMetrics(context.Background(), keywordplanner.HistoricalMetricsRequest{Keywords: []string{"go"}}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if elapsed := time.Since(start); elapsed < 50*time.Millisecond {
+		t.Errorf("retried after %v, want at least the 50ms retry delay", elapsed)
+	}
+}
+
+// TestPost_RetryDelayBeyondMaxBackoff_IsNotRetried verifies a quota that resets
+// later than the policy is willing to wait fails straight away.
+func TestPost_RetryDelayBeyondMaxBackoff_IsNotRetried(t *testing.T) {
+	t.Parallel()
+
+	var calls atomic.Int32
+	srv := countingServer(t, &calls, func(w http.ResponseWriter) {
+		w.Header().Set("Retry-After", "3600")
+		respondStatus(http.StatusTooManyRequests, `{"error": {"code": 429, "message": "quota", "status": "RESOURCE_EXHAUSTED"}}`)(w)
+	})
+	defer srv.Close()
+
+	client := keywordplanner.NewTestClient("dev-token", "123", "", srv.URL, srv.Client())
+	client.SetRetryPolicy(keywordplanner.RetryPolicy{MaxAttempts: 3, InitialBackoff: time.Millisecond, MaxBackoff: time.Second})
+
+	_, err := client.GetHistoricalMetrics(context.Background(), keywordplanner.HistoricalMetricsRequest{Keywords: []string{"go"}})
+	var apiErr *keywordplanner.APIError
+	if !errors.As(err, &apiErr) || apiErr.RetryDelay != time.Hour {
+		t.Errorf("err = %v, want an APIError with a one hour RetryDelay", err)
+	}
+	if n := calls.Load(); n != 1 {
+		t.Errorf("requests = %d, want 1", n)
+	}
+}
+
+// TestPost_ContextDeadline_StopsRetrying verifies no retry is attempted when the
+// backoff would outlast the caller's deadline.
+func TestPost_ContextDeadline_StopsRetrying(t *testing.T) {
+	t.Parallel()
+
+	var calls atomic.Int32
+	srv := countingServer(t, &calls, respondStatus(http.StatusInternalServerError, `{"error": {"code": 500, "message": "internal", "status": "INTERNAL"}}`))
+	defer srv.Close()
+
+	client := keywordplanner.NewTestClient("dev-token", "123", "", srv.URL, srv.Client())
+	client.SetRetryPolicy(keywordplanner.RetryPolicy{MaxAttempts: 5, InitialBackoff: 10 * time.Second, MaxBackoff: time.Minute})
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+	start := time.Now()
+	if _, err := client.GetHistoricalMetrics(ctx, keywordplanner.HistoricalMetricsRequest{Keywords: []string{"go"}}); err == nil {
+		t.Fatal("expected error, got nil")
+	}
+	if elapsed := time.Since(start); elapsed > 500*time.Millisecond {
+		t.Errorf("returned after %v, want an immediate failure", elapsed)
+	}
+	if n := calls.Load(); n != 1 {
+		t.Errorf("requests = %d, want 1", n)
+	}
+}
+
+// TestPost_DailyOperationsExhausted_FailsWithoutCallingAPI verifies requests beyond
+// the daily budget fail with a QuotaExhaustedError naming the next Pacific midnight.
+func TestPost_DailyOperationsExhausted_FailsWithoutCallingAPI(t *testing.T) {
+	t.Parallel()
+
+	var calls atomic.Int32
+	srv := countingServer(t, &calls, respondStatus(http.StatusOK, `{"metrics": []}`))
+	defer srv.Close()
+
+	client := keywordplanner.NewTestClient("dev-token", "123", "", srv.URL, srv.Client())
+	client.SetClock(func() time.Time { return time.Date(2025, time.October, 1, 15, 0, 0, 0, time.UTC) })
+	if err := client.SetLimits(keywordplanner.Limits{DailyOperations: 2}); err != nil {
+		t.Fatalf("SetLimits: %v", err)
+	}
+
+	req := keywordplanner.HistoricalMetricsRequest{Keywords: []string{"go"}}
+	for range 2 {
+		if _, err := client.GetHistoricalMetrics(context.Background(), req); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+	}
+	_, err := client.GetHistoricalMetrics(context.Background(), req)
+	var quotaErr *keywordplanner.QuotaExhaustedError
+	if !errors.As(err, &quotaErr) {
+		t.Fatalf("err = %v, want a QuotaExhaustedError", err)
+	}
+	if !strings.Contains(err.Error(), "quota budget exhausted, resets at") {
+		t.Errorf("error = %q, want it to say when the budget resets", err)
+	}
+	// 15:00 UTC on 1 October is 08:00 PDT, so the quota day ends at 07:00 UTC on 2 October.
+	if want := time.Date(2025, time.October, 2, 7, 0, 0, 0, time.UTC); !quotaErr.ResetsAt.Equal(want) {
+		t.Errorf("ResetsAt = %v, want %v", quotaErr.ResetsAt, want)
+	}
+	if n := calls.Load(); n != 2 {
+		t.Errorf("requests = %d, want 2", n)
+	}
+}
+
+// TestSetLimits_StateFile_PersistsCountAcrossClients verifies a new client loading
+// the same state file continues the day's count.
+func TestSetLimits_StateFile_PersistsCountAcrossClients(t *testing.T) {
+	t.Parallel()
+
+	var calls atomic.Int32
+	srv := countingServer(t, &calls, respondStatus(http.StatusOK, `{"metrics": []}`))
+	defer srv.Close()
+
+	limits := keywordplanner.Limits{DailyOperations: 1, StateFile: filepath.Join(t.TempDir(), "state", "quota.json")}
+	now := func() time.Time { return time.Date(2025, time.October, 1, 15, 0, 0, 0, time.UTC) }
+	req := keywordplanner.HistoricalMetricsRequest{Keywords: []string{"go"}}
+
+	first := keywordplanner.NewTestClient("dev-token", "123", "", srv.URL, srv.Client())
+	first.SetClock(now)
+	if err := first.SetLimits(limits); err != nil {
+		t.Fatalf("SetLimits: %v", err)
+	}
+	if _, err := first.GetHistoricalMetrics(context.Background(), req); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	second := keywordplanner.NewTestClient("dev-token", "123", "", srv.URL, srv.Client())
+	second.SetClock(now)
+	if err := second.SetLimits(limits); err != nil {
+		t.Fatalf("SetLimits: %v", err)
+	}
+	_, err := second.GetHistoricalMetrics(context.Background(), req)
+	var quotaErr *keywordplanner.QuotaExhaustedError
+	if !errors.As(err, &quotaErr) {
+		t.Errorf("err = %v, want a QuotaExhaustedError", err)
+	}
+
+	// The count starts over on the next quota day.
+	second.SetClock(func() time.Time { return time.Date(2025, time.October, 2, 15, 0, 0, 0, time.UTC) })
+	if _, err := second.GetHistoricalMetrics(context.Background(), req); err != nil {
+		t.Errorf("next day: unexpected error: %v", err)
+	}
+}
+
+// TestSetLimits_StateFile_SharedBetweenClients verifies clients using the same state
+// file at the same time draw on one daily budget.
+func TestSetLimits_StateFile_SharedBetweenClients(t *testing.T) {
+	t.Parallel()
+
+	var calls atomic.Int32
+	srv := countingServer(t, &calls, respondStatus(http.StatusOK, `{"metrics": []}`))
+	defer srv.Close()
+
+	stateFile := filepath.Join(t.TempDir(), "quota.json")
+	limits := keywordplanner.Limits{DailyOperations: 5, StateFile: stateFile}
+	clients := make([]*keywordplanner.Client, 2)
+	for i := range clients {
+		clients[i] = keywordplanner.NewTestClient("dev-token", "123", "", srv.URL, srv.Client())
+		if err := clients[i].SetLimits(limits); err != nil {
+			t.Fatalf("SetLimits: %v", err)
+		}
+	}
+
+	var succeeded atomic.Int32
+	var wg sync.WaitGroup
+	for i := range 8 {
+		wg.Go(func() {
+			req := keywordplanner.HistoricalMetricsRequest{Keywords: []string{"kw" + strconv.Itoa(i)}}
+			if _, err := clients[i%2].GetHistoricalMetrics(context.Background(), req); err == nil {
+				succeeded.Add(1)
+			}
+		})
+	}
+	wg.Wait()
+
+	if n := succeeded.Load(); n != 5 {
+		t.Errorf("successful requests = %d, want 5", n)
+	}
+	b, err := os.ReadFile(stateFile)
+	if err != nil {
+		t.Fatalf("reading state file: %v", err)
+	}
+	var state struct {
+		Operations int `json:"operations"`
+	}
+	if err := json.Unmarshal(b, &state); err != nil || state.Operations != 5 {
+		t.Errorf("state file = %s, want 5 operations", b)
+	}
+}
+
+// TestSetLimits_CorruptStateFile_CountsFromZero verifies a state file that does not
+// parse is treated as an empty count rather than an error.
+func TestSetLimits_CorruptStateFile_CountsFromZero(t *testing.T) {
+	t.Parallel()
+
+	var calls atomic.Int32
+	srv := countingServer(t, &calls, respondStatus(http.StatusOK, `{"metrics": []}`))
+	defer srv.Close()
+
+	stateFile := filepath.Join(t.TempDir(), "quota.json")
+	if err := os.WriteFile(stateFile, []byte("{not json"), 0o644); err != nil {
+		t.Fatalf("writing state file: %v", err)
+	}
+	client := keywordplanner.NewTestClient("dev-token", "123", "", srv.URL, srv.Client())
+	if err := client.SetLimits(keywordplanner.Limits{DailyOperations: 1, StateFile: stateFile}); err != nil {
+		t.Fatalf("SetLimits: %v", err)
+	}
+	if _, err := client.GetHistoricalMetrics(context.Background(), keywordplanner.HistoricalMetricsRequest{Keywords: []string{"go"}}); err != nil {
+		t.Errorf("unexpected error: %v", err)
+	}
+}
+
+// TestSetLimits_StaleStateLock_IsTakenOver verifies a lock file left behind by a
+// crashed process is taken over once stale, and released after use.
+func TestSetLimits_StaleStateLock_IsTakenOver(t *testing.T) {
+	t.Parallel()
+
+	var calls atomic.Int32
+	srv := countingServer(t, &calls, respondStatus(http.StatusOK, `{"metrics": []}`))
+	defer srv.Close()
+
+	stateFile := filepath.Join(t.TempDir(), "quota.json")
+	lockPath := stateFile + ".lock"
+	if err := os.WriteFile(lockPath, []byte("crashed-holder"), 0o644); err != nil {
+		t.Fatalf("writing lock file: %v", err)
+	}
+	old := time.Now().Add(-time.Minute)
+	if err := os.Chtimes(lockPath, old, old); err != nil {
+		t.Fatalf("aging lock file: %v", err)
+	}
+
+	client := keywordplanner.NewTestClient("dev-token", "123", "", srv.URL, srv.Client())
+	if err := client.SetLimits(keywordplanner.Limits{DailyOperations: 1, StateFile: stateFile}); err != nil {
+		t.Fatalf("SetLimits: %v", err)
+	}
+	if _, err := client.GetHistoricalMetrics(context.Background(), keywordplanner.HistoricalMetricsRequest{Keywords: []string{"go"}}); err != nil {
+		t.Errorf("unexpected error: %v", err)
+	}
+	if _, err := os.Stat(lockPath); !errors.Is(err, fs.ErrNotExist) {
+		t.Errorf("lock file stat err = %v, want it removed", err)
+	}
+	entries, err := os.ReadDir(filepath.Dir(stateFile))
+	if err != nil {
+		t.Fatalf("reading state directory: %v", err)
+	}
+	for _, e := range entries {
+		if strings.HasSuffix(e.Name(), ".stale") {
+			t.Errorf("stale lock %s left behind", e.Name())
+		}
+	}
+}
+
+// TestPost_RateLimit_SpacesRequests verifies requests beyond the burst wait for the
+// token bucket to refill.
+func TestPost_RateLimit_SpacesRequests(t *testing.T) {
+	t.Parallel()
+
+	var calls atomic.Int32
+	srv := countingServer(t, &calls, respondStatus(http.StatusOK, `{"metrics": []}`))
+	defer srv.Close()
+
+	client := keywordplanner.NewTestClient("dev-token", "123", "", srv.URL, srv.Client())
+	if err := client.SetLimits(keywordplanner.Limits{RequestsPerSecond: 20, Burst: 1}); err != nil {
+		t.Fatalf("SetLimits: %v", err)
+	}
+
+	start := time.Now()
+	for range 3 {
+		if _, err := client.GetHistoricalMetrics(context.Background(), keywordplanner.HistoricalMetricsRequest{Keywords: []string{"go"}}); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+	}
+	if elapsed := time.Since(start); elapsed < 90*time.Millisecond {
+		t.Errorf("3 requests took %v, want at least 100ms at 20 per second", elapsed)
+	}
+}
+
+// TestPost_RateLimit_FrozenClock_StillRefills verifies the token bucket refills in
+// real time, so a fixed client clock does not make each wait longer than the last.
+func TestPost_RateLimit_FrozenClock_StillRefills(t *testing.T) {
+	t.Parallel()
+
+	var calls atomic.Int32
+	srv := countingServer(t, &calls, respondStatus(http.StatusOK, `{"metrics": []}`))
+	defer srv.Close()
+
+	client := keywordplanner.NewTestClient("dev-token", "123", "", srv.URL, srv.Client())
+	client.SetClock(func() time.Time { return time.Date(2025, time.October, 1, 15, 0, 0, 0, time.UTC) })
+	if err := client.SetLimits(keywordplanner.Limits{RequestsPerSecond: 20, Burst: 1, DailyOperations: 10}); err != nil {
+		t.Fatalf("SetLimits: %v", err)
+	}
+
+	start := time.Now()
+	for range 6 {
+		if _, err := client.GetHistoricalMetrics(context.Background(), keywordplanner.HistoricalMetricsRequest{Keywords: []string{"go"}}); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+	}
+	// Five 50ms waits take 250ms; a bucket that never refilled would wait 750ms.
+	if elapsed := time.Since(start); elapsed > 500*time.Millisecond {
+		t.Errorf("6 requests took %v, want about 250ms at 20 per second", elapsed)
+	}
+}
+
+// TestPost_RateLimit_ContextCanceledWhileWaiting verifies a caller waiting for the
+// rate limit gives up when its context ends.
+func TestPost_RateLimit_ContextCanceledWhileWaiting(t *testing.T) {
+	t.Parallel()
+
+	var calls atomic.Int32
+	srv := countingServer(t, &calls, respondStatus(http.StatusOK, `{"metrics": []}`))
+	defer srv.Close()
+
+	client := keywordplanner.NewTestClient("dev-token", "123", "", srv.URL, srv.Client())
+	if err := client.SetLimits(keywordplanner.Limits{RequestsPerSecond: 0.01, Burst: 1}); err != nil {
+		t.Fatalf("SetLimits: %v", err)
+	}
+	req := keywordplanner.HistoricalMetricsRequest{Keywords: []string{"go"}}
+	if _, err := client.GetHistoricalMetrics(context.Background(), req); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+	if _, err := client.GetHistoricalMetrics(ctx, req); !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("err = %v, want context.DeadlineExceeded", err)
+	}
+	if n := calls.Load(); n != 1 {
+		t.Errorf("requests = %d, want 1", n)
+	}
+}
+
+// TestPost_RateLimit_CanceledWaitUsesNoBudget verifies a caller that gives up
+// waiting for the rate limit does not use up a daily operation.
+func TestPost_RateLimit_CanceledWaitUsesNoBudget(t *testing.T) {
+	t.Parallel()
+
+	var calls atomic.Int32
+	srv := countingServer(t, &calls, respondStatus(http.StatusOK, `{"metrics": []}`))
+	defer srv.Close()
+
+	client := keywordplanner.NewTestClient("dev-token", "123", "", srv.URL, srv.Client())
+	if err := client.SetLimits(keywordplanner.Limits{RequestsPerSecond: 10, Burst: 1, DailyOperations: 2}); err != nil {
+		t.Fatalf("SetLimits: %v", err)
+	}
+	req := keywordplanner.HistoricalMetricsRequest{Keywords: []string{"go"}}
+	if _, err := client.GetHistoricalMetrics(context.Background(), req); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Millisecond)
+	defer cancel()
+	if _, err := client.GetHistoricalMetrics(ctx, keywordplanner.HistoricalMetricsRequest{Keywords: []string{"rust"}}); !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("err = %v, want context.DeadlineExceeded", err)
+	}
+
+	if _, err := client.GetHistoricalMetrics(context.Background(), keywordplanner.HistoricalMetricsRequest{Keywords: []string{"python"}}); err != nil {
+		t.Errorf("unexpected error: %v, want the canceled call's operation to remain", err)
+	}
+	if n := calls.Load(); n != 2 {
+		t.Errorf("requests = %d, want 2", n)
+	}
+}
+
+// TestGenerateKeywordIdeas_ManySeeds_SplitIntoChunks verifies more than 20 seeds are
+// requested in chunks, and the merged ideas keep chunk order, name their chunk and
+// appear once even when several chunks produce them.
+func TestGenerateKeywordIdeas_ManySeeds_SplitIntoChunks(t *testing.T) {
+	t.Parallel()
+
+	var requests atomic.Int32
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests.Add(1)
+		var body struct {
+			KeywordSeed struct {
+				Keywords []string `json:"keywords"`
+			} `json:"keywordSeed"`
+		}
+		_ = json.NewDecoder(r.Body).Decode(&body)
+		seeds := body.KeywordSeed.Keywords
+		if len(seeds) > keywordplanner.MaxSeedKeywordsPerRequest {
+			t.Errorf("request has %d seeds, want at most %d", len(seeds), keywordplanner.MaxSeedKeywordsPerRequest)
+		}
+		w.Header().Set("Content-Type", "application/json")
+		_ = json.NewEncoder(w).Encode(map[string]any{
+			"results": []map[string]any{{"text": seeds[0] + " idea"}, {"text": "shared idea"}},
+		})
+	}))
+	defer srv.Close()
+
+	seeds := make([]string, 45)
+	for i := range seeds {
+		seeds[i] = fmt.Sprintf("seed%d", i)
+	}
+	client := keywordplanner.NewTestClient("dev-token", "123", "", srv.URL, srv.Client())
+	resp, err := client.GenerateKeywordIdeas(context.Background(), keywordplanner.KeywordIdeasRequest{SeedKeywords: seeds})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if n := requests.Load(); n != 3 {
+		t.Errorf("requests = %d, want 3", n)
+	}
+	if len(resp.SeedChunks) != 3 || len(resp.SeedChunks[0]) != 20 || len(resp.SeedChunks[2]) != 5 {
+		t.Errorf("SeedChunks sizes wrong: %v", resp.SeedChunks)
+	}
+	type idea struct {
+		text  string
+		chunk int
+	}
+	var got []idea
+	for _, i := range resp.Ideas {
+		got = append(got, idea{i.Text, i.SeedChunk})
+	}
+	want := []idea{{"seed0 idea", 1}, {"shared idea", 1}, {"seed20 idea", 2}, {"seed40 idea", 3}}
+	if !slices.Equal(got, want) {
+		t.Errorf("ideas = %v, want %v", got, want)
+	}
+	if resp.Count != len(want) || resp.NextPageToken != "" {
+		t.Errorf("Count = %d, NextPageToken = %q; want %d and no token", resp.Count, resp.NextPageToken, len(want))
+	}
+}
+
+// TestGenerateKeywordIdeas_ManySeedsWithMaxIdeas_CapsAcrossChunks verifies MaxIdeas
+// caps the ideas of all seed chunks together, so later chunks ask only for what is
+// left, and that the device breakdown is left out of split results.
+func TestGenerateKeywordIdeas_ManySeedsWithMaxIdeas_CapsAcrossChunks(t *testing.T) {
+	t.Parallel()
+
+	var (
+		mu        sync.Mutex
+		pageSizes []int
+	)
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var body struct {
+			PageSize    int `json:"pageSize"`
+			KeywordSeed struct {
+				Keywords []string `json:"keywords"`
+			} `json:"keywordSeed"`
+		}
+		_ = json.NewDecoder(r.Body).Decode(&body)
+		mu.Lock()
+		pageSizes = append(pageSizes, body.PageSize)
+		mu.Unlock()
+		var results []map[string]any
+		for i := range min(body.PageSize, 3) {
+			results = append(results, map[string]any{"text": fmt.Sprintf("%s idea %d", body.KeywordSeed.Keywords[0], i)})
+		}
+		w.Header().Set("Content-Type", "application/json")
+		_ = json.NewEncoder(w).Encode(map[string]any{
+			"results":                results,
+			"aggregateMetricResults": map[string]any{"deviceSearches": []map[string]any{{"device": "MOBILE", "searchCount": "100"}}},
+		})
+	}))
+	defer srv.Close()
+
+	seeds := make([]string, 45)
+	for i := range seeds {
+		seeds[i] = fmt.Sprintf("seed%d", i)
+	}
+	client := keywordplanner.NewTestClient("dev-token", "123", "", srv.URL, srv.Client())
+	resp, err := client.GenerateKeywordIdeas(context.Background(), keywordplanner.KeywordIdeasRequest{
+		SeedKeywords:      seeds,
+		MaxIdeas:          5,
+		BreakdownByDevice: true,
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if !slices.Equal(pageSizes, []int{5, 2}) {
+		t.Errorf("page sizes = %v, want [5 2]", pageSizes)
+	}
+	if resp.Count != 5 {
+		t.Errorf("Count = %d, want 5", resp.Count)
+	}
+	if resp.DeviceSearches != nil {
+		t.Errorf("DeviceSearches = %v, want none for split seeds", resp.DeviceSearches)
+	}
+}
+
+// TestGenerateKeywordIdeas_ManySeedsWithPageToken_ReturnsError verifies a page token
+// is rejected when the seeds would be split, since it belongs to a single request.
+func TestGenerateKeywordIdeas_ManySeedsWithPageToken_ReturnsError(t *testing.T) {
+	t.Parallel()
+
+	client := keywordplanner.NewTestClient("dev-token", "123", "", "http://127.0.0.1:0", http.DefaultClient)
+	seeds := make([]string, keywordplanner.MaxSeedKeywordsPerRequest+1)
+	for i := range seeds {
+		seeds[i] = fmt.Sprintf("seed%d", i)
+	}
+	_, err := client.GenerateKeywordIdeas(context.Background(), keywordplanner.KeywordIdeasRequest{SeedKeywords: seeds, PageToken: "abc"})
+	if err == nil || !strings.Contains(err.Error(), "page token") {
+		t.Errorf("err = %v, want a page token error", err)
+	}
+}
+
+// TestGetHistoricalMetrics_ManyKeywords_SplitIntoChunks verifies keyword lists over
+// the per-request limit are split and the metrics come back in input order.
+func TestGetHistoricalMetrics_ManyKeywords_SplitIntoChunks(t *testing.T) {
+	t.Parallel()
+
+	var requests atomic.Int32
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests.Add(1)
+		var body struct {
+			Keywords []string `json:"keywords"`
+		}
+		_ = json.NewDecoder(r.Body).Decode(&body)
+		if len(body.Keywords) > keywordplanner.MaxHistoricalKeywordsPerRequest {
+			t.Errorf("request has %d keywords, want at most %d", len(body.Keywords), keywordplanner.MaxHistoricalKeywordsPerRequest)
+		}
+		// The later, smaller chunk answers first.
+		if len(body.Keywords) == keywordplanner.MaxHistoricalKeywordsPerRequest {
+			time.Sleep(20 * time.Millisecond)
+		}
+		metrics := make([]map[string]any, len(body.Keywords))
+		for i, k := range body.Keywords {
+			metrics[i] = map[string]any{"text": k, "keywordMetrics": map[string]any{"avgMonthlySearches": "10"}}
+		}
+		w.Header().Set("Content-Type", "application/json")
+		_ = json.NewEncoder(w).Encode(map[string]any{"metrics": metrics})
+	}))
+	defer srv.Close()
+
+	keywords := make([]string, keywordplanner.MaxHistoricalKeywordsPerRequest+5)
+	for i := range keywords {
+		keywords[i] = "kw" + strconv.Itoa(i)
+	}
+	client := keywordplanner.NewTestClient("dev-token", "123", "", srv.URL, srv.Client())
+	resp, err := client.GetHistoricalMetrics(context.Background(), keywordplanner.HistoricalMetricsRequest{Keywords: keywords})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if n := requests.Load(); n != 2 {
+		t.Errorf("requests = %d, want 2", n)
+	}
+	if resp.Count != len(keywords) {
+		t.Fatalf("Count = %d, want %d", resp.Count, len(keywords))
+	}
+	for i, m := range resp.Keywords {
+		if m.Text != keywords[i] {
+			t.Fatalf("Keywords[%d] = %q, want %q", i, m.Text, keywords[i])
+		}
+	}
+}
+
+// TestGetKeywordForecast_ManyKeywords_SplitsAdGroupAcrossRequests verifies an ad group
+// over the per-request limit is forecast in parts whose metrics are totalled.
+func TestGetKeywordForecast_ManyKeywords_SplitsAdGroupAcrossRequests(t *testing.T) {
+	t.Parallel()
+
+	var requests atomic.Int32
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests.Add(1)
+		var body struct {
+			CampaignForecastSpec struct {
+				AdGroups []struct {
+					BiddableKeywords []struct {
+						Keyword struct {
+							Text string `json:"text"`
+						} `json:"keyword"`
+					} `json:"biddableKeywords"`
+				} `json:"adGroups"`
+			} `json:"campaignForecastSpec"`
+		}
+		_ = json.NewDecoder(r.Body).Decode(&body)
+		var adGroups []map[string]any
+		total := 0
+		for _, ag := range body.CampaignForecastSpec.AdGroups {
+			var keywords []map[string]any
+			for _, kw := range ag.BiddableKeywords {
+				keywords = append(keywords, map[string]any{
+					"keyword": map[string]any{"text": kw.Keyword.Text, "matchType": "BROAD"},
+					"metrics": map[string]any{"clicks": 1, "costMicros": "1000"},
+				})
+			}
+			total += len(keywords)
+			adGroups = append(adGroups, map[string]any{"keywordForecastMetrics": keywords})
+		}
+		if total > keywordplanner.MaxForecastKeywordsPerRequest {
+			t.Errorf("request has %d keywords, want at most %d", total, keywordplanner.MaxForecastKeywordsPerRequest)
+		}
+		w.Header().Set("Content-Type", "application/json")
+		_ = json.NewEncoder(w).Encode(map[string]any{
+			"campaignForecastMetrics": map[string]any{"clicks": total, "costMicros": strconv.Itoa(total * 1000)},
+			"adGroupForecastMetrics":  adGroups,
+		})
+	}))
+	defer srv.Close()
+
+	keywords := make([]keywordplanner.ForecastKeyword, keywordplanner.MaxForecastKeywordsPerRequest+10)
+	for i := range keywords {
+		keywords[i] = keywordplanner.ForecastKeyword{Text: "kw" + strconv.Itoa(i)}
+	}
+	client := keywordplanner.NewTestClient("dev-token", "123", "", srv.URL, srv.Client())
+	resp, err := client.GetKeywordForecast(context.Background(), keywordplanner.ForecastRequest{Keywords: keywords})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if n := requests.Load(); n != 2 {
+		t.Errorf("requests = %d, want 2", n)
+	}
+	if len(resp.Keywords) != len(keywords) || resp.Keywords[len(keywords)-1].Text != keywords[len(keywords)-1].Text {
+		t.Errorf("got %d keyword forecasts, want %d in input order", len(resp.Keywords), len(keywords))
+	}
+	if want := float64(len(keywords)); resp.Campaign.Clicks != want || resp.AdGroups[keywordplanner.DefaultAdGroupName].Clicks != want {
+		t.Errorf("campaign clicks = %v, ad group clicks = %v; want both %v",
+			resp.Campaign.Clicks, resp.AdGroups[keywordplanner.DefaultAdGroupName].Clicks, want)
+	}
+	if resp.Campaign.AverageCPCMicros != 1000 {
+		t.Errorf("campaign average CPC = %v, want 1000", resp.Campaign.AverageCPCMicros)
+	}
+}
+
+// TestGetKeywordForecast_ManyKeywordsWithDailyBudget_ReturnsError verifies a forecast
+// that needs several requests refuses a daily budget it could not share.
+func TestGetKeywordForecast_ManyKeywordsWithDailyBudget_ReturnsError(t *testing.T) {
+	t.Parallel()
+
+	keywords := make([]keywordplanner.ForecastKeyword, keywordplanner.MaxForecastKeywordsPerRequest+1)
+	for i := range keywords {
+		keywords[i] = keywordplanner.ForecastKeyword{Text: "kw" + strconv.Itoa(i)}
+	}
+	client := keywordplanner.NewTestClient("dev-token", "123", "", "http://127.0.0.1:0", http.DefaultClient)
+	_, err := client.GetKeywordForecast(context.Background(), keywordplanner.ForecastRequest{
+		Keywords:        keywords,
+		BiddingStrategy: keywordplanner.BiddingStrategy{Type: keywordplanner.BiddingStrategyMaximizeClicks, DailyBudgetMicros: 10_000_000},
+	})
+	if err == nil || !strings.Contains(err.Error(), "daily budget") {
+		t.Errorf("err = %v, want a daily budget error", err)
+	}
+}
+
+// TestSetCache_RepeatedRequest_ServedFromCache verifies an identical request is
+// answered from the cache, marked as cached with its original fetch time.
+func TestSetCache_RepeatedRequest_ServedFromCache(t *testing.T) {
+	t.Parallel()
+
+	var calls atomic.Int32
+	srv := countingServer(t, &calls, respondStatus(http.StatusOK, `{"metrics": [{"text": "go"}]}`))
+	defer srv.Close()
+
+	fetched := time.Date(2025, time.October, 1, 15, 0, 0, 0, time.UTC)
+	now := fetched
+	client := keywordplanner.NewTestClient("dev-token", "123", "", srv.URL, srv.Client())
+	client.SetClock(func() time.Time { return now })
+	client.SetCache(keywordplanner.CacheOptions{MaxEntries: 10, TTL: time.Hour})
+
+	req := keywordplanner.HistoricalMetricsRequest{Keywords: []string{"go"}}
+	first, err := client.GetHistoricalMetrics(context.Background(), req)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if first.Cached {
+		t.Error("first response is marked cached")
+	}
+
+	now = fetched.Add(30 * time.Minute)
+	second, err := client.GetHistoricalMetrics(context.Background(), req)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !second.Cached || !second.FetchedAt.Equal(fetched) {
+		t.Errorf("CacheInfo = %+v, want cached as of %v", second.CacheInfo, fetched)
+	}
+	if second.Count != 1 {
+		t.Errorf("Count = %d, want 1", second.Count)
+	}
+	if n := calls.Load(); n != 1 {
+		t.Errorf("requests = %d, want 1", n)
+	}
+}
+
+// TestSetCache_ExpiredEntry_IsRefetched verifies responses older than the TTL are
+// fetched again.
+func TestSetCache_ExpiredEntry_IsRefetched(t *testing.T) {
+	t.Parallel()
+
+	var calls atomic.Int32
+	srv := countingServer(t, &calls, respondStatus(http.StatusOK, `{"metrics": []}`))
+	defer srv.Close()
+
+	now := time.Date(2025, time.October, 1, 15, 0, 0, 0, time.UTC)
+	client := keywordplanner.NewTestClient("dev-token", "123", "", srv.URL, srv.Client())
+	client.SetClock(func() time.Time { return now })
+	client.SetCache(keywordplanner.CacheOptions{MaxEntries: 10, TTL: time.Minute})
+
+	req := keywordplanner.HistoricalMetricsRequest{Keywords: []string{"go"}}
+	if _, err := client.GetHistoricalMetrics(context.Background(), req); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	now = now.Add(time.Minute)
+	resp, err := client.GetHistoricalMetrics(context.Background(), req)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if resp.Cached {
+		t.Error("expired response is marked cached")
+	}
+	if n := calls.Load(); n != 2 {
+		t.Errorf("requests = %d, want 2", n)
+	}
+}
+
+// TestSetCache_LeastRecentlyUsed_IsEvicted verifies the cache holds at most
+// MaxEntries responses, dropping the least recently used.
+func TestSetCache_LeastRecentlyUsed_IsEvicted(t *testing.T) {
+	t.Parallel()
+
+	var calls atomic.Int32
+	srv := countingServer(t, &calls, respondStatus(http.StatusOK, `{"metrics": []}`))
+	defer srv.Close()
+
+	client := keywordplanner.NewTestClient("dev-token", "123", "", srv.URL, srv.Client())
+	client.SetCache(keywordplanner.CacheOptions{MaxEntries: 2, TTL: time.Hour})
+
+	lookup := func(keyword string) {
+		t.Helper()
+		if _, err := client.GetHistoricalMetrics(context.Background(), keywordplanner.HistoricalMetricsRequest{Keywords: []string{keyword}}); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+	}
+	lookup("a")
+	lookup("b")
+	lookup("a") // cached; b is now least recently used
+	lookup("c") // evicts b
+	lookup("a") // still cached
+	if n := calls.Load(); n != 3 {
+		t.Errorf("requests = %d, want 3", n)
+	}
+	lookup("b")
+	if n := calls.Load(); n != 4 {
+		t.Errorf("requests = %d, want b to be fetched again", n)
+	}
+}
+
+// TestWithoutCache_BypassesCache verifies requests made with WithoutCache neither
+// read from nor add to the cache.
+func TestWithoutCache_BypassesCache(t *testing.T) {
+	t.Parallel()
+
+	var calls atomic.Int32
+	srv := countingServer(t, &calls, respondStatus(http.StatusOK, `{"metrics": []}`))
+	defer srv.Close()
+
+	client := keywordplanner.NewTestClient("dev-token", "123", "", srv.URL, srv.Client())
+	client.SetCache(keywordplanner.CacheOptions{MaxEntries: 10, TTL: time.Hour})
+
+	req := keywordplanner.HistoricalMetricsRequest{Keywords: []string{"go"}}
+	uncached := keywordplanner.WithoutCache(context.Background())
+	for range 2 {
+		if _, err := client.GetHistoricalMetrics(uncached, req); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+	}
+	resp, err := client.GetHistoricalMetrics(context.Background(), req)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if resp.Cached {
+		t.Error("response is marked cached, want the bypassed requests not to be stored")
+	}
+	if n := calls.Load(); n != 3 {
+		t.Errorf("requests = %d, want 3", n)
+	}
+}
+
+// TestSetDiskCache_SharedAcrossClients verifies a response written to the cache
+// directory by one client is served to another, as after a restart.
+func TestSetDiskCache_SharedAcrossClients(t *testing.T) {
+	t.Parallel()
+
+	var calls atomic.Int32
+	srv := countingServer(t, &calls, respondStatus(http.StatusOK, `{"metrics": [{"text": "go"}]}`))
+	defer srv.Close()
+
+	opts := keywordplanner.DiskCacheOptions{Dir: t.TempDir(), TTL: time.Hour}
+	fetched := time.Date(2025, time.October, 1, 15, 0, 0, 0, time.UTC)
+	req := keywordplanner.HistoricalMetricsRequest{Keywords: []string{"go"}}
+
+	first := keywordplanner.NewTestClient("dev-token", "123", "", srv.URL, srv.Client())
+	first.SetClock(func() time.Time { return fetched })
+	if err := first.SetDiskCache(opts); err != nil {
+		t.Fatalf("SetDiskCache: %v", err)
+	}
+	if _, err := first.GetHistoricalMetrics(context.Background(), req); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	second := keywordplanner.NewTestClient("dev-token", "123", "", srv.URL, srv.Client())
+	second.SetClock(func() time.Time { return fetched.Add(30 * time.Minute) })
+	if err := second.SetDiskCache(opts); err != nil {
+		t.Fatalf("SetDiskCache: %v", err)
+	}
+	resp, err := second.GetHistoricalMetrics(context.Background(), req)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !resp.Cached || !resp.FetchedAt.Equal(fetched) || resp.Count != 1 {
+		t.Errorf("response = %+v, want the cached metrics fetched at %v", resp, fetched)
+	}
+	if n := calls.Load(); n != 1 {
+		t.Errorf("requests = %d, want 1", n)
+	}
+}
+
+// TestSetDiskCache_ExpiredEntry_IsRefetched verifies an entry is not served past the
+// expiry recorded when it was written.
+func TestSetDiskCache_ExpiredEntry_IsRefetched(t *testing.T) {
+	t.Parallel()
+
+	var calls atomic.Int32
+	srv := countingServer(t, &calls, respondStatus(http.StatusOK, `{"metrics": []}`))
+	defer srv.Close()
+
+	now := time.Date(2025, time.October, 1, 15, 0, 0, 0, time.UTC)
+	client := keywordplanner.NewTestClient("dev-token", "123", "", srv.URL, srv.Client())
+	client.SetClock(func() time.Time { return now })
+	if err := client.SetDiskCache(keywordplanner.DiskCacheOptions{Dir: t.TempDir(), TTL: time.Hour}); err != nil {
+		t.Fatalf("SetDiskCache: %v", err)
+	}
+
+	req := keywordplanner.HistoricalMetricsRequest{Keywords: []string{"go"}}
+	for _, advance := range []time.Duration{0, 59 * time.Minute, 2 * time.Minute} {
+		now = now.Add(advance)
+		if _, err := client.GetHistoricalMetrics(context.Background(), req); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+	}
+	if n := calls.Load(); n != 2 {
+		t.Errorf("requests = %d, want 2", n)
+	}
+}
+
+// TestSetDiskCache_MaxBytes_EvictsLeastRecentlyUsed verifies the cache directory is
+// kept within MaxBytes by removing the entries used longest ago.
+func TestSetDiskCache_MaxBytes_EvictsLeastRecentlyUsed(t *testing.T) {
+	t.Parallel()
+
+	var calls atomic.Int32
+	srv := countingServer(t, &calls, respondStatus(http.StatusOK, `{"metrics": []}`))
+	defer srv.Close()
+
+	dir := t.TempDir()
+	client := keywordplanner.NewTestClient("dev-token", "123", "", srv.URL, srv.Client())
+	lookup := func(keyword string) {
+		t.Helper()
+		if _, err := client.GetHistoricalMetrics(context.Background(), keywordplanner.HistoricalMetricsRequest{Keywords: []string{keyword}}); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		// Modification times order the entries; keep them apart on coarse clocks.
+		time.Sleep(20 * time.Millisecond)
+	}
+
+	// Size the cap to hold exactly two entries.
+	if err := client.SetDiskCache(keywordplanner.DiskCacheOptions{Dir: dir, TTL: time.Hour}); err != nil {
+		t.Fatalf("SetDiskCache: %v", err)
+	}
+	lookup("a")
+	entrySize := diskCacheSize(t, dir)
+	if err := client.SetDiskCache(keywordplanner.DiskCacheOptions{Dir: dir, TTL: time.Hour, MaxBytes: 2 * entrySize}); err != nil {
+		t.Fatalf("SetDiskCache: %v", err)
+	}
+
+	lookup("b")
+	lookup("a") // cached; b is now least recently used
+	lookup("c") // evicts b
+	if n := calls.Load(); n != 3 {
+		t.Fatalf("requests = %d, want 3", n)
+	}
+	if size := diskCacheSize(t, dir); size > 2*entrySize {
+		t.Errorf("cache size = %d, want at most %d", size, 2*entrySize)
+	}
+	lookup("a")
+	if n := calls.Load(); n != 3 {
+		t.Errorf("requests = %d, want a to still be cached", n)
+	}
+	lookup("b")
+	if n := calls.Load(); n != 4 {
+		t.Errorf("requests = %d, want b to be fetched again", n)
+	}
+}
+
+// TestSetDiskCache_Forecasts_AreNotStored verifies only keyword ideas and historical
+// metrics are written to disk.
+func TestSetDiskCache_Forecasts_AreNotStored(t *testing.T) {
+	t.Parallel()
+
+	var calls atomic.Int32
+	srv := countingServer(t, &calls, respondStatus(http.StatusOK, `{"adGroupForecastMetrics": []}`))
+	defer srv.Close()
+
+	dir := t.TempDir()
+	client := keywordplanner.NewTestClient("dev-token", "123", "", srv.URL, srv.Client())
+	if err := client.SetDiskCache(keywordplanner.DiskCacheOptions{Dir: dir, TTL: time.Hour}); err != nil {
+		t.Fatalf("SetDiskCache: %v", err)
+	}
+	req := keywordplanner.ForecastRequest{Keywords: []keywordplanner.ForecastKeyword{{Text: "go"}}}
+	if _, err := client.GetKeywordForecast(context.Background(), req); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if size := diskCacheSize(t, dir); size != 0 {
+		t.Errorf("cache size = %d, want nothing stored", size)
+	}
+}
+
+// TestSetDiskCache_ConcurrentClients_ShareDirectory verifies clients writing to the
+// same directory at once all succeed and leave no lock or temporary files behind.
+func TestSetDiskCache_ConcurrentClients_ShareDirectory(t *testing.T) {
+	t.Parallel()
+
+	var calls atomic.Int32
+	srv := countingServer(t, &calls, respondStatus(http.StatusOK, `{"metrics": []}`))
+	defer srv.Close()
+
+	dir := t.TempDir()
+	errs := make(chan error, 8)
+	for i := range 8 {
+		go func() {
+			client := keywordplanner.NewTestClient("dev-token", "123", "", srv.URL, srv.Client())
+			if err := client.SetDiskCache(keywordplanner.DiskCacheOptions{Dir: dir, TTL: time.Hour, MaxBytes: 1 << 20}); err != nil {
+				errs <- err
+				return
+			}
+			_, err := client.GetHistoricalMetrics(context.Background(), keywordplanner.HistoricalMetricsRequest{Keywords: []string{strconv.Itoa(i % 4)}})
+			errs <- err
+		}()
+	}
+	for range 8 {
+		if err := <-errs; err != nil {
+			t.Errorf("unexpected error: %v", err)
+		}
+	}
+
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		t.Fatalf("ReadDir: %v", err)
+	}
+	for _, e := range entries {
+		if !strings.HasSuffix(e.Name(), ".json") || strings.HasPrefix(e.Name(), ".") {
+			t.Errorf("unexpected file %s left in the cache directory", e.Name())
+		}
+	}
+	if len(entries) != 4 {
+		t.Errorf("got %d entries, want 4", len(entries))
+	}
+}
+
+// diskCacheSize returns the total size of the entry files in dir.
+func diskCacheSize(t *testing.T, dir string) int64 {
+	t.Helper()
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		t.Fatalf("ReadDir: %v", err)
+	}
+	var total int64
+	for _, e := range entries {
+		if !strings.HasSuffix(e.Name(), ".json") {
+			continue
+		}
+		info, err := e.Info()
+		if err != nil {
+			t.Fatalf("Info: %v", err)
+		}
+		total += info.Size()
+	}
+	return total
+}
+
+// blockingServer answers every request with body once release is closed, counting
+// the requests received.
+func blockingServer(t *testing.T, calls *atomic.Int32, release <-chan struct{}, body string) *httptest.Server {
+	t.Helper()
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls.Add(1)
+		select {
+		case <-release:
+		case <-r.Context().Done():
+			return
+		}
+		respondStatus(http.StatusOK, body)(w)
+	}))
+}
+
+// TestPost_ConcurrentIdenticalRequests_ShareOneCall verifies callers making the same
+// request at the same time share a single upstream call and each get the result.
+func TestPost_ConcurrentIdenticalRequests_ShareOneCall(t *testing.T) {
+	t.Parallel()
+
+	var calls atomic.Int32
+	release := make(chan struct{})
+	srv := blockingServer(t, &calls, release, `{"results": [{"text": "go tutorial"}]}`)
+	defer srv.Close()
+
+	client := keywordplanner.NewTestClient("dev-token", "123", "", srv.URL, srv.Client())
+	req := keywordplanner.KeywordIdeasRequest{SeedKeywords: []string{"go"}}
+
+	const callers = 5
+	results := make(chan *keywordplanner.KeywordIdeasResponse, callers)
+	errs := make(chan error, callers)
+	for range callers {
+		go func() {
+			resp, err := client.GenerateKeywordIdeas(context.Background(), req)
+			errs <- err
+			results <- resp
+		}()
+	}
+	// Let every caller join the in-flight request before it completes.
+	time.Sleep(50 * time.Millisecond)
+	close(release)
+
+	for range callers {
+		if err := <-errs; err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if resp := <-results; resp.Count != 1 || resp.Ideas[0].Text != "go tutorial" {
+			t.Errorf("response = %+v, want the shared idea", resp)
+		}
+	}
+	if n := calls.Load(); n != 1 {
+		t.Errorf("requests = %d, want 1", n)
+	}
+}
+
+// TestPost_ConcurrentDifferentRequests_AreNotShared verifies only identical request
+// bodies are coalesced.
+func TestPost_ConcurrentDifferentRequests_AreNotShared(t *testing.T) {
+	t.Parallel()
+
+	var calls atomic.Int32
+	release := make(chan struct{})
+	srv := blockingServer(t, &calls, release, `{"results": []}`)
+	defer srv.Close()
+
+	client := keywordplanner.NewTestClient("dev-token", "123", "", srv.URL, srv.Client())
+	errs := make(chan error, 2)
+	for _, seed := range []string{"go", "rust"} {
+		go func() {
+			_, err := client.GenerateKeywordIdeas(context.Background(), keywordplanner.KeywordIdeasRequest{SeedKeywords: []string{seed}})
+			errs <- err
+		}()
+	}
+	time.Sleep(50 * time.Millisecond)
+	close(release)
+	for range 2 {
+		if err := <-errs; err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+	}
+	if n := calls.Load(); n != 2 {
+		t.Errorf("requests = %d, want 2", n)
+	}
+}
+
+// TestPost_SharedRequestCanceled_WaiterSendsItsOwn verifies a caller waiting on
+// another's request is not failed by that caller's cancellation.
+func TestPost_SharedRequestCanceled_WaiterSendsItsOwn(t *testing.T) {
+	t.Parallel()
+
+	var calls atomic.Int32
+	release := make(chan struct{})
+	srv := blockingServer(t, &calls, release, `{"results": []}`)
+	defer srv.Close()
+
+	client := keywordplanner.NewTestClient("dev-token", "123", "", srv.URL, srv.Client())
+	req := keywordplanner.KeywordIdeasRequest{SeedKeywords: []string{"go"}}
+
+	leaderCtx, cancelLeader := context.WithCancel(context.Background())
+	leaderErr := make(chan error, 1)
+	go func() {
+		_, err := client.GenerateKeywordIdeas(leaderCtx, req)
+		leaderErr <- err
+	}()
+	time.Sleep(20 * time.Millisecond)
+
+	waiterErr := make(chan error, 1)
+	go func() {
+		_, err := client.GenerateKeywordIdeas(context.Background(), req)
+		waiterErr <- err
+	}()
+	time.Sleep(20 * time.Millisecond)
+	cancelLeader()
+	if err := <-leaderErr; !errors.Is(err, context.Canceled) {
+		t.Errorf("leader err = %v, want context.Canceled", err)
+	}
+
+	close(release)
+	if err := <-waiterErr; err != nil {
+		t.Errorf("waiter err = %v, want its own request to succeed", err)
+	}
+	if n := calls.Load(); n != 2 {
+		t.Errorf("requests = %d, want 2", n)
+	}
+}
+
+// historicalEchoServer answers historical metrics requests with a result for each
+// requested keyword, normalized as the API does, and records the keyword count of the
+// last request in lastSize.
+func historicalEchoServer(t *testing.T, calls, lastSize *atomic.Int32, delay time.Duration) *httptest.Server {
+	t.Helper()
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls.Add(1)
+		var body struct {
+			Keywords []string `json:"keywords"`
+		}
+		_ = json.NewDecoder(r.Body).Decode(&body)
+		lastSize.Store(int32(len(body.Keywords)))
+		time.Sleep(delay)
+		metrics := make([]map[string]any, len(body.Keywords))
+		for i, k := range body.Keywords {
+			metrics[i] = map[string]any{"text": strings.Join(strings.Fields(strings.ToLower(k)), " "), "keywordMetrics": map[string]any{"avgMonthlySearches": "10"}}
+		}
+		w.Header().Set("Content-Type", "application/json")
+		_ = json.NewEncoder(w).Encode(map[string]any{"metrics": metrics})
+	}))
+}
+
+// TestSetHistoricalBatchWindow_ConcurrentLookups_ShareOneRequest verifies lookups
+// made within the window are sent as one request and each caller gets only the
+// metrics of its own keywords.
+func TestSetHistoricalBatchWindow_ConcurrentLookups_ShareOneRequest(t *testing.T) {
+	t.Parallel()
+
+	var calls, lastSize atomic.Int32
+	srv := historicalEchoServer(t, &calls, &lastSize, 0)
+	defer srv.Close()
+
+	client := keywordplanner.NewTestClient("dev-token", "123", "", srv.URL, srv.Client())
+	client.SetHistoricalBatchWindow(100 * time.Millisecond)
+
+	lookups := [][]string{{"Go Tutorial"}, {"rust"}, {"python", "go  tutorial"}}
+	results := make([]*keywordplanner.HistoricalMetricsResponse, len(lookups))
+	errs := make([]error, len(lookups))
+	var wg sync.WaitGroup
+	for i, keywords := range lookups {
+		wg.Go(func() {
+			results[i], errs[i] = client.GetHistoricalMetrics(context.Background(), keywordplanner.HistoricalMetricsRequest{Keywords: keywords})
+		})
+	}
+	wg.Wait()
+
+	if n := calls.Load(); n != 1 {
+		t.Fatalf("requests = %d, want 1", n)
+	}
+	if n := lastSize.Load(); n != 3 {
+		t.Errorf("request keywords = %d, want 3 with the repeated keyword sent once", n)
+	}
+	want := [][]string{{"go tutorial"}, {"rust"}, {"go tutorial", "python"}}
+	for i := range lookups {
+		if errs[i] != nil {
+			t.Fatalf("lookup %d: unexpected error: %v", i, errs[i])
+		}
+		var got []string
+		for _, m := range results[i].Keywords {
+			got = append(got, m.Text)
+		}
+		slices.Sort(got)
+		if !slices.Equal(got, want[i]) {
+			t.Errorf("lookup %d keywords = %v, want %v", i, got, want[i])
+		}
+	}
+}
+
+// TestSetHistoricalBatchWindow_DifferentOptions_AreNotMerged verifies only lookups
+// with the same options share a request.
+func TestSetHistoricalBatchWindow_DifferentOptions_AreNotMerged(t *testing.T) {
+	t.Parallel()
+
+	var calls, lastSize atomic.Int32
+	srv := historicalEchoServer(t, &calls, &lastSize, 0)
+	defer srv.Close()
+
+	client := keywordplanner.NewTestClient("dev-token", "123", "", srv.URL, srv.Client())
+	client.SetHistoricalBatchWindow(50 * time.Millisecond)
+
+	var wg sync.WaitGroup
+	for _, cpc := range []bool{false, true} {
+		wg.Go(func() {
+			req := keywordplanner.HistoricalMetricsRequest{Keywords: []string{"go"}, IncludeAverageCPC: cpc}
+			if _, err := client.GetHistoricalMetrics(context.Background(), req); err != nil {
+				t.Errorf("unexpected error: %v", err)
+			}
+		})
+	}
+	wg.Wait()
+
+	if n := calls.Load(); n != 2 {
+		t.Errorf("requests = %d, want 2", n)
+	}
+}
+
+// TestSetHistoricalBatchWindow_DeviceBreakdown_IsSentImmediately verifies lookups
+// with a device breakdown, whose totals cannot be split between callers, do not wait
+// for a batch.
+func TestSetHistoricalBatchWindow_DeviceBreakdown_IsSentImmediately(t *testing.T) {
+	t.Parallel()
+
+	var calls, lastSize atomic.Int32
+	srv := historicalEchoServer(t, &calls, &lastSize, 0)
+	defer srv.Close()
+
+	client := keywordplanner.NewTestClient("dev-token", "123", "", srv.URL, srv.Client())
+	client.SetHistoricalBatchWindow(time.Hour)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	req := keywordplanner.HistoricalMetricsRequest{Keywords: []string{"go"}, BreakdownByDevice: true}
+	if _, err := client.GetHistoricalMetrics(ctx, req); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+}
+
+// TestSetHistoricalBatchWindow_FullBatch_SentBeforeWindow verifies a batch that
+// reaches the per-request keyword limit is sent without waiting out the window.
+func TestSetHistoricalBatchWindow_FullBatch_SentBeforeWindow(t *testing.T) {
+	t.Parallel()
+
+	var calls, lastSize atomic.Int32
+	srv := historicalEchoServer(t, &calls, &lastSize, 0)
+	defer srv.Close()
+
+	client := keywordplanner.NewTestClient("dev-token", "123", "", srv.URL, srv.Client())
+	client.SetHistoricalBatchWindow(time.Hour)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	half := keywordplanner.MaxHistoricalKeywordsPerRequest / 2
+	var wg sync.WaitGroup
+	for part := range 2 {
+		wg.Go(func() {
+			keywords := make([]string, half)
+			for i := range keywords {
+				keywords[i] = "kw" + strconv.Itoa(part*half+i)
+			}
+			resp, err := client.GetHistoricalMetrics(ctx, keywordplanner.HistoricalMetricsRequest{Keywords: keywords})
+			if err != nil {
+				t.Errorf("unexpected error: %v", err)
+				return
+			}
+			if resp.Count != half {
+				t.Errorf("Count = %d, want %d", resp.Count, half)
+			}
+		})
+	}
+	wg.Wait()
+
+	if n := calls.Load(); n != 1 {
+		t.Errorf("requests = %d, want 1", n)
+	}
+	if n := lastSize.Load(); int(n) != keywordplanner.MaxHistoricalKeywordsPerRequest {
+		t.Errorf("request keywords = %d, want %d", n, keywordplanner.MaxHistoricalKeywordsPerRequest)
+	}
+}
+
+// TestSetHistoricalBatchWindow_UpstreamError_ReachesEveryCaller verifies a failed
+// batch request fails each lookup in it.
+func TestSetHistoricalBatchWindow_UpstreamError_ReachesEveryCaller(t *testing.T) {
+	t.Parallel()
+
+	var calls atomic.Int32
+	srv := countingServer(t, &calls,
+		respondStatus(http.StatusBadRequest, `{"error": {"code": 400, "message": "bad keyword", "status": "INVALID_ARGUMENT"}}`),
+	)
+	defer srv.Close()
+
+	client := keywordplanner.NewTestClient("dev-token", "123", "", srv.URL, srv.Client())
+	client.SetHistoricalBatchWindow(50 * time.Millisecond)
+
+	var wg sync.WaitGroup
+	for _, keyword := range []string{"go", "rust"} {
+		wg.Go(func() {
+			_, err := client.GetHistoricalMetrics(context.Background(), keywordplanner.HistoricalMetricsRequest{Keywords: []string{keyword}})
+			if err == nil || !strings.Contains(err.Error(), "bad keyword") {
+				t.Errorf("err = %v, want the upstream error", err)
+			}
+		})
+	}
+	wg.Wait()
+
+	if n := calls.Load(); n != 1 {
+		t.Errorf("requests = %d, want 1", n)
+	}
+}
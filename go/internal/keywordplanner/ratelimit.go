@@ -0,0 +1,268 @@
+package keywordplanner
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Limits throttles the requests a Client makes so that Google's own limits are not
+// hit: a token bucket per endpoint, and a cap on operations per day.
+type Limits struct {
+	// RequestsPerSecond is the sustained request rate allowed to each endpoint; 0
+	// disables rate limiting.
+	RequestsPerSecond float64
+	// Burst is how many requests an endpoint may receive at once after being idle.
+	// Values below 1 are treated as 1.
+	Burst int
+	// DailyOperations caps the requests made per Google Ads quota day, which ends
+	// at midnight Pacific Time; 0 disables the cap. Every attempt counts, including
+	// retries.
+	DailyOperations int
+	// StateFile, when set, keeps the daily operation count in a file so that it
+	// survives restarts and is shared by every process using the same file.
+	StateFile string
+}
+
+// QuotaExhaustedError is returned, without calling the API, once the daily
+// operation budget in Limits is used up.
+type QuotaExhaustedError struct {
+	// Limit is the configured number of daily operations.
+	Limit int
+	// ResetsAt is when the budget is replenished.
+	ResetsAt time.Time
+}
+
+func (e *QuotaExhaustedError) Error() string {
+	return fmt.Sprintf("quota budget exhausted, resets at %s (all %d daily operations used)",
+		e.ResetsAt.Format(time.RFC3339), e.Limit)
+}
+
+// SetLimits throttles the client's requests according to l. When l.StateFile is
+// set, an error is returned if the file cannot be locked or read; a file that does
+// not parse counts as no operations.
+func (c *Client) SetLimits(l Limits) error {
+	lim := &limiter{limits: l, buckets: make(map[string]*tokenBucket), location: quotaLocation()}
+	if l.StateFile != "" {
+		if err := os.MkdirAll(filepath.Dir(l.StateFile), 0o755); err != nil {
+			return fmt.Errorf("creating quota state directory: %w", err)
+		}
+		if _, err := lim.updateStateFile(func(*quotaState) bool { return false }); err != nil {
+			return err
+		}
+	}
+	c.limiter = lim
+	return nil
+}
+
+// limiter enforces Limits for one Client. A nil limiter allows everything.
+type limiter struct {
+	limits   Limits
+	location *time.Location
+
+	mu      sync.Mutex
+	buckets map[string]*tokenBucket
+	// state is the day's operation count as last seen in StateFile, or the only count
+	// when there is no StateFile or it cannot be used.
+	state quotaState
+}
+
+// quotaState is the StateFile format.
+type quotaState struct {
+	Day        string `json:"day"`
+	Operations int    `json:"operations"`
+}
+
+// acquire waits for endpoint's rate limit and then records one operation against
+// the daily budget, or fails if the budget is used up or ctx ends first. A caller
+// that gives up while waiting uses none of the budget. The token buckets refill in
+// real time; clock only decides which day the operation counts against.
+func (l *limiter) acquire(ctx context.Context, endpoint string, clock func() time.Time) error {
+	if l == nil {
+		return nil
+	}
+
+	var wait time.Duration
+	if l.limits.RequestsPerSecond > 0 {
+		l.mu.Lock()
+		name := endpointName(endpoint)
+		now := time.Now()
+		bucket, ok := l.buckets[name]
+		if !ok {
+			bucket = newTokenBucket(l.limits.RequestsPerSecond, l.limits.Burst, now)
+			l.buckets[name] = bucket
+		}
+		wait = bucket.take(now)
+		l.mu.Unlock()
+	}
+	if wait > 0 {
+		timer := time.NewTimer(wait)
+		defer timer.Stop()
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-timer.C:
+		}
+	}
+
+	if l.limits.DailyOperations <= 0 {
+		return nil
+	}
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	return l.reserveLocked(clock())
+}
+
+// reserveLocked records one operation against the daily budget, or returns a
+// QuotaExhaustedError if it is used up. The caller must hold l.mu.
+func (l *limiter) reserveLocked(now time.Time) error {
+	quotaNow := now.In(l.location)
+	day := quotaNow.Format(dateLayout)
+	take := func(state *quotaState) bool {
+		if state.Day != day {
+			*state = quotaState{Day: day}
+		}
+		if state.Operations >= l.limits.DailyOperations {
+			return false
+		}
+		state.Operations++
+		return true
+	}
+
+	var ok bool
+	if l.limits.StateFile == "" {
+		ok = take(&l.state)
+	} else {
+		var err error
+		// A state file that cannot be used only stops the count being shared, which
+		// is no reason to fail the request; it is counted in memory instead.
+		if ok, err = l.updateStateFile(take); err != nil {
+			ok = take(&l.state)
+		}
+	}
+	if !ok {
+		y, m, d := quotaNow.Date()
+		return &QuotaExhaustedError{
+			Limit:    l.limits.DailyOperations,
+			ResetsAt: time.Date(y, m, d+1, 0, 0, 0, 0, l.location),
+		}
+	}
+	return nil
+}
+
+// updateStateFile applies update to the count in StateFile while holding its lock
+// file, so that processes sharing the file never overwrite each other's counts, and
+// writes the count back when update returns true. It returns update's result.
+func (l *limiter) updateStateFile(update func(*quotaState) bool) (bool, error) {
+	path := l.limits.StateFile
+	unlock, err := lockFile(path + ".lock")
+	if err != nil {
+		return false, fmt.Errorf("locking quota state: %w", err)
+	}
+	defer unlock()
+
+	state, err := readQuotaState(path)
+	if err != nil {
+		return false, err
+	}
+	changed := update(&state)
+	l.state = state
+	if changed {
+		// A failed write only leaves this operation out of the shared count.
+		_ = writeQuotaState(path, state)
+	}
+	return changed, nil
+}
+
+// readQuotaState reads the count saved at path. A missing file, or one that does
+// not parse, is an empty count.
+func readQuotaState(path string) (quotaState, error) {
+	b, err := os.ReadFile(path)
+	if errors.Is(err, fs.ErrNotExist) {
+		return quotaState{}, nil
+	}
+	if err != nil {
+		return quotaState{}, fmt.Errorf("reading quota state: %w", err)
+	}
+	var state quotaState
+	if json.Unmarshal(b, &state) != nil {
+		return quotaState{}, nil
+	}
+	return state, nil
+}
+
+// writeQuotaState writes state to path through a temporary file, so a crash
+// mid-write never leaves a truncated file behind.
+func writeQuotaState(path string, state quotaState) error {
+	b, err := json.Marshal(state)
+	if err != nil {
+		return fmt.Errorf("marshalling quota state: %w", err)
+	}
+	tmp, err := os.CreateTemp(filepath.Dir(path), filepath.Base(path)+".tmp-*")
+	if err != nil {
+		return fmt.Errorf("writing quota state: %w", err)
+	}
+	_, err = tmp.Write(b)
+	if closeErr := tmp.Close(); err == nil {
+		err = closeErr
+	}
+	if err == nil {
+		err = os.Rename(tmp.Name(), path)
+	}
+	if err != nil {
+		_ = os.Remove(tmp.Name())
+		return fmt.Errorf("writing quota state: %w", err)
+	}
+	return nil
+}
+
+// tokenBucket is a token bucket rate limiter. Tokens may go negative: each taker
+// is told how long to wait for its token, so waiters are served in order.
+type tokenBucket struct {
+	rate   float64
+	burst  float64
+	tokens float64
+	last   time.Time
+}
+
+func newTokenBucket(rate float64, burst int, now time.Time) *tokenBucket {
+	b := float64(max(burst, 1))
+	return &tokenBucket{rate: rate, burst: b, tokens: b, last: now}
+}
+
+// take removes a token and returns how long the caller must wait for it.
+func (b *tokenBucket) take(now time.Time) time.Duration {
+	if elapsed := now.Sub(b.last); elapsed > 0 {
+		b.tokens = min(b.burst, b.tokens+elapsed.Seconds()*b.rate)
+		b.last = now
+	}
+	b.tokens--
+	if b.tokens >= 0 {
+		return 0
+	}
+	return time.Duration(-b.tokens / b.rate * float64(time.Second))
+}
+
+// endpointName returns the RPC method of an endpoint URL, such as
+// generateKeywordIdeas, so that each customer endpoint is limited separately.
+func endpointName(endpoint string) string {
+	if i := strings.LastIndex(endpoint, ":"); i >= 0 {
+		return endpoint[i+1:]
+	}
+	return endpoint
+}
+
+// quotaLocation returns the time zone Google Ads quota days are counted in.
+func quotaLocation() *time.Location {
+	if loc, err := time.LoadLocation("America/Los_Angeles"); err == nil {
+		return loc
+	}
+	return time.FixedZone("PST", -8*60*60)
+}
@@ -0,0 +1,115 @@
+package keywordplanner
+
+import (
+	"context"
+	"errors"
+	"math/rand/v2"
+	"net"
+	"net/http"
+	"time"
+
+	"golang.org/x/oauth2"
+)
+
+// Defaults for RetryPolicy fields left zero.
+const (
+	DefaultRetryMaxAttempts    = 3
+	DefaultRetryInitialBackoff = time.Second
+	DefaultRetryMaxBackoff     = 30 * time.Second
+)
+
+// RetryPolicy controls how Client retries transient Google Ads API failures: 429s,
+// 5xx responses, RESOURCE_EXHAUSTED and UNAVAILABLE errors, and network errors.
+// Rejected requests, such as validation and permission errors, are never retried.
+// All Keyword Planner endpoints are read-only, so retrying them is safe.
+type RetryPolicy struct {
+	// MaxAttempts is the total number of attempts, including the first. 1 disables
+	// retries.
+	MaxAttempts int
+	// InitialBackoff is the base delay before the first retry. It doubles with each
+	// further retry, and a random jitter of up to half of it is subtracted.
+	InitialBackoff time.Duration
+	// MaxBackoff caps the delay between attempts. A retry delay requested by Google
+	// longer than this is not waited out; the error is returned instead.
+	MaxBackoff time.Duration
+}
+
+// DefaultRetryPolicy returns the policy clients use unless SetRetryPolicy is called.
+func DefaultRetryPolicy() RetryPolicy {
+	return RetryPolicy{
+		MaxAttempts:    DefaultRetryMaxAttempts,
+		InitialBackoff: DefaultRetryInitialBackoff,
+		MaxBackoff:     DefaultRetryMaxBackoff,
+	}
+}
+
+// SetRetryPolicy replaces the client's retry policy. Zero fields take their defaults.
+func (c *Client) SetRetryPolicy(p RetryPolicy) {
+	if p.MaxAttempts <= 0 {
+		p.MaxAttempts = DefaultRetryMaxAttempts
+	}
+	if p.InitialBackoff <= 0 {
+		p.InitialBackoff = DefaultRetryInitialBackoff
+	}
+	if p.MaxBackoff <= 0 {
+		p.MaxBackoff = DefaultRetryMaxBackoff
+	}
+	c.retry = p
+}
+
+// delay reports whether the failed attempt number attempt should be retried after
+// err, and how long to wait first. It gives up when the wait would outlast ctx's
+// deadline, since the retry could not complete anyway.
+func (p RetryPolicy) delay(ctx context.Context, attempt int, err error) (time.Duration, bool) {
+	if attempt >= p.MaxAttempts || ctx.Err() != nil || !isRetryable(err) {
+		return 0, false
+	}
+
+	backoff := p.InitialBackoff << (attempt - 1)
+	if backoff > p.MaxBackoff || backoff <= 0 {
+		backoff = p.MaxBackoff
+	}
+	wait := backoff - rand.N(backoff/2+1)
+
+	var apiErr *APIError
+	if errors.As(err, &apiErr) && apiErr.RetryDelay > 0 {
+		if apiErr.RetryDelay > p.MaxBackoff {
+			return 0, false
+		}
+		wait = max(wait, apiErr.RetryDelay)
+	}
+
+	if deadline, ok := ctx.Deadline(); ok && time.Until(deadline) < wait {
+		return 0, false
+	}
+	return wait, true
+}
+
+// isRetryable reports whether err is a transient failure worth another attempt.
+func isRetryable(err error) bool {
+	var apiErr *APIError
+	if errors.As(err, &apiErr) {
+		switch apiErr.Status {
+		case "RESOURCE_EXHAUSTED", "UNAVAILABLE":
+			return true
+		}
+		switch apiErr.HTTPStatus {
+		case http.StatusTooManyRequests, http.StatusInternalServerError, http.StatusBadGateway,
+			http.StatusServiceUnavailable, http.StatusGatewayTimeout:
+			return true
+		}
+		return false
+	}
+	// Refusals from the OAuth token endpoint, such as a revoked refresh token, will
+	// not resolve themselves.
+	var tokenErr *oauth2.RetrieveError
+	if errors.As(err, &tokenErr) {
+		return false
+	}
+	if isContextError(err) {
+		return false
+	}
+	// Network failures, such as a dropped connection, are worth another attempt.
+	var netErr net.Error
+	return errors.As(err, &netErr)
+}
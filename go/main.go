@@ -7,6 +7,12 @@
 //	    [--listen-address <address>] [--port <port>] [--allowed-hosts <list>]
 //	    [--developer-token <token>] [--client-id <id>] [--client-secret <secret>]
 //	    [--refresh-token <token>] [--customer-id <id>]
+//	    [--retry-max-attempts <n>] [--retry-initial-backoff <duration>]
+//	    [--retry-max-backoff <duration>] [--rate-limit <per-second>] [--rate-burst <n>]
+//	    [--daily-operations <n>] [--quota-state-file <path>]
+//	    [--cache-size <n>] [--cache-ttl <duration>] [--cache-tools <list>]
+//	    [--cache-dir <path>] [--cache-disk-ttl <duration>] [--cache-max-bytes <n>]
+//	    [--historical-batch-window <duration>]
 //
 // Credential resolution order: CLI flags > environment variables > .env file.
 // When --transport http, MCP_LISTEN_ADDRESS and PORT configure the listener.
@@ -15,13 +21,16 @@ package main
 import (
 	"context"
 	"encoding/json"
+	"errors"
 	"flag"
 	"fmt"
 	"log/slog"
 	"os"
 	"os/signal"
+	"slices"
 	"strings"
 	"syscall"
+	"time"
 
 	"github.com/modelcontextprotocol/go-sdk/mcp"
 	"github.com/ncosentino/google-keyword-planner-mcp/go/internal/config"
@@ -44,6 +53,34 @@ func main() {
 		"HTTP listen address (default MCP_LISTEN_ADDRESS or 127.0.0.1)",
 	)
 	port := flag.Int("port", 0, "HTTP listen port (default PORT or 8080)")
+	retryMaxAttempts := flag.String("retry-max-attempts", "",
+		"Attempts per Google Ads API request, including the first; 1 disables retries (default GOOGLE_ADS_RETRY_MAX_ATTEMPTS or 3)")
+	retryInitialBackoff := flag.String("retry-initial-backoff", "",
+		"Delay before the first retry, doubling after each (default GOOGLE_ADS_RETRY_INITIAL_BACKOFF or 1s)")
+	retryMaxBackoff := flag.String("retry-max-backoff", "",
+		"Longest delay between retries (default GOOGLE_ADS_RETRY_MAX_BACKOFF or 30s)")
+	rateLimit := flag.String("rate-limit", "",
+		"Requests per second allowed to each Google Ads endpoint; 0 disables (default GOOGLE_ADS_RATE_LIMIT or 1)")
+	rateBurst := flag.String("rate-burst", "",
+		"Requests an idle endpoint accepts at once (default GOOGLE_ADS_RATE_BURST or 3)")
+	dailyOperations := flag.String("daily-operations", "",
+		"Google Ads API operations allowed per day; 0 disables (default GOOGLE_ADS_DAILY_OPERATIONS or 15000)")
+	quotaStateFile := flag.String("quota-state-file", "",
+		"File keeping the daily operation count across restarts, shared by every process using it (default GOOGLE_ADS_QUOTA_STATE_FILE or the user cache directory)")
+	cacheSize := flag.String("cache-size", "",
+		"Google Ads API responses kept in memory; 0 disables caching (default GOOGLE_ADS_CACHE_SIZE or 500)")
+	cacheTTL := flag.String("cache-ttl", "",
+		"How long a cached response is reused (default GOOGLE_ADS_CACHE_TTL or 15m)")
+	cacheTools := flag.String("cache-tools", "",
+		"Comma-separated tools whose responses are cached (default GOOGLE_ADS_CACHE_TOOLS or all tools)")
+	cacheDir := flag.String("cache-dir", "",
+		"Directory caching keyword ideas and historical metrics across restarts; shareable between processes (default GOOGLE_ADS_CACHE_DIR or none)")
+	cacheDiskTTL := flag.String("cache-disk-ttl", "",
+		"How long a response cached in --cache-dir is reused (default GOOGLE_ADS_CACHE_DISK_TTL or 24h)")
+	cacheMaxBytes := flag.String("cache-max-bytes", "",
+		"Size above which the least recently used --cache-dir entries are removed; 0 means no cap (default GOOGLE_ADS_CACHE_MAX_BYTES or 100 MiB)")
+	historicalBatchWindow := flag.String("historical-batch-window", "",
+		"How long a historical metrics lookup waits to share one API request with others, e.g. 50ms (default GOOGLE_ADS_HISTORICAL_BATCH_WINDOW or no batching)")
 	allowedHosts := flag.String("allowed-hosts", "localhost,127.0.0.1,[::1]",
 		"Comma-separated Host header allow-list for --transport http (protects against DNS rebinding)")
 	flag.Parse()
@@ -72,11 +109,81 @@ func main() {
 		os.Exit(1)
 	}
 
+	retry, err := config.ResolveRetry(config.RetryFlags{
+		MaxAttempts:    *retryMaxAttempts,
+		InitialBackoff: *retryInitialBackoff,
+		MaxBackoff:     *retryMaxBackoff,
+	})
+	if err != nil {
+		slog.Error("invalid retry settings", "err", err)
+		os.Exit(1)
+	}
+
+	limits, err := config.ResolveLimits(config.LimitsFlags{
+		RateLimit:       *rateLimit,
+		RateBurst:       *rateBurst,
+		DailyOperations: *dailyOperations,
+		QuotaStateFile:  *quotaStateFile,
+	})
+	if err != nil {
+		slog.Error("invalid limit settings", "err", err)
+		os.Exit(1)
+	}
+
+	cache, err := config.ResolveCache(config.CacheFlags{
+		Size:     *cacheSize,
+		TTL:      *cacheTTL,
+		Tools:    *cacheTools,
+		Dir:      *cacheDir,
+		DiskTTL:  *cacheDiskTTL,
+		MaxBytes: *cacheMaxBytes,
+	})
+	if err != nil {
+		slog.Error("invalid cache settings", "err", err)
+		os.Exit(1)
+	}
+	cachedTools, err := resolveCachedTools(cache.Tools)
+	if err != nil {
+		slog.Error("invalid cache settings", "err", err)
+		os.Exit(1)
+	}
+
+	batchWindow, err := config.ResolveHistoricalBatchWindow(*historicalBatchWindow)
+	if err != nil {
+		slog.Error("invalid batch settings", "err", err)
+		os.Exit(1)
+	}
+
 	client := keywordplanner.NewClient(
 		cfg.DeveloperToken, cfg.ClientID, cfg.ClientSecret, cfg.RefreshToken, cfg.CustomerID, cfg.LoginCustomerID,
 	)
+	client.SetRetryPolicy(keywordplanner.RetryPolicy{
+		MaxAttempts:    retry.MaxAttempts,
+		InitialBackoff: retry.InitialBackoff,
+		MaxBackoff:     retry.MaxBackoff,
+	})
+	if err := client.SetLimits(keywordplanner.Limits{
+		RequestsPerSecond: limits.RateLimit,
+		Burst:             limits.RateBurst,
+		DailyOperations:   limits.DailyOperations,
+		StateFile:         limits.QuotaStateFile,
+	}); err != nil {
+		slog.Error("loading quota state", "err", err)
+		os.Exit(1)
+	}
 
-	srv := newServer(client)
+	client.SetCache(keywordplanner.CacheOptions{MaxEntries: cache.Size, TTL: cache.TTL})
+	if err := client.SetDiskCache(keywordplanner.DiskCacheOptions{
+		Dir:      cache.Dir,
+		TTL:      cache.DiskTTL,
+		MaxBytes: cache.MaxBytes,
+	}); err != nil {
+		slog.Error("opening cache directory", "err", err)
+		os.Exit(1)
+	}
+	client.SetHistoricalBatchWindow(batchWindow)
+
+	srv := newServer(client, serverOptions{CachedTools: cachedTools})
 
 	switch *transport {
 	case "http":
@@ -120,9 +227,49 @@ func main() {
 	}
 }
 
+// toolNames lists the tools newServer registers.
+var toolNames = []string{
+	"generate_keyword_ideas",
+	"get_historical_metrics",
+	"get_keyword_forecast",
+	"get_bid_landscape",
+	"plan_for_budget",
+}
+
+// serverOptions configures the tools newServer registers.
+type serverOptions struct {
+	// CachedTools names the tools whose Google Ads API responses may be served from,
+	// and stored in, the client's cache.
+	CachedTools map[string]bool
+}
+
+// toolContext returns the context the named tool calls the client with.
+func (o serverOptions) toolContext(ctx context.Context, tool string) context.Context {
+	if o.CachedTools[tool] {
+		return ctx
+	}
+	return keywordplanner.WithoutCache(ctx)
+}
+
+// resolveCachedTools validates the configured cache tool names. An empty list
+// enables caching for every tool.
+func resolveCachedTools(names []string) (map[string]bool, error) {
+	if len(names) == 0 {
+		names = toolNames
+	}
+	cached := make(map[string]bool, len(names))
+	for _, name := range names {
+		if !slices.Contains(toolNames, name) {
+			return nil, fmt.Errorf("unknown tool %q in cache tools; expected one of %s", name, strings.Join(toolNames, ", "))
+		}
+		cached[name] = true
+	}
+	return cached, nil
+}
+
 // newServer builds the MCP server with all tools and middleware registered. It is
 // independent of which transport (stdio or http) will ultimately serve it.
-func newServer(client *keywordplanner.Client) *mcp.Server {
+func newServer(client *keywordplanner.Client, opts serverOptions) *mcp.Server {
 	srv := mcp.NewServer(&mcp.Implementation{
 		Name:    "google-keyword-planner-mcp",
 		Version: version,
@@ -135,10 +282,10 @@ func newServer(client *keywordplanner.Client) *mcp.Server {
 	mcp.AddTool(srv,
 		&mcp.Tool{
 			Name:        "generate_keyword_ideas",
-			Description: "Generate keyword ideas from seed keywords and/or a URL using Google Ads Keyword Planner. Returns related keywords with average monthly search volume, competition level, and CPC estimates.",
+			Description: "Generate keyword ideas from seed keywords and/or a URL, or from an entire site, using Google Ads Keyword Planner. Returns related keywords with average monthly search volume, competition level, and CPC estimates.",
 		},
 		func(ctx context.Context, _ *mcp.CallToolRequest, input generateKeywordIdeasInput) (*mcp.CallToolResult, any, error) {
-			return generateKeywordIdeas(ctx, client, input)
+			return generateKeywordIdeas(opts.toolContext(ctx, "generate_keyword_ideas"), client, input)
 		},
 	)
 
@@ -148,7 +295,7 @@ func newServer(client *keywordplanner.Client) *mcp.Server {
 			Description: "Get historical search volume and competition metrics for a list of specific keywords using Google Ads Keyword Planner.",
 		},
 		func(ctx context.Context, _ *mcp.CallToolRequest, input getHistoricalMetricsInput) (*mcp.CallToolResult, any, error) {
-			return getHistoricalMetrics(ctx, client, input)
+			return getHistoricalMetrics(opts.toolContext(ctx, "get_historical_metrics"), client, input)
 		},
 	)
 
@@ -158,7 +305,27 @@ func newServer(client *keywordplanner.Client) *mcp.Server {
 			Description: "Get projected impressions, clicks, and cost for a set of keywords at a given max CPC bid using Google Ads Keyword Planner.",
 		},
 		func(ctx context.Context, _ *mcp.CallToolRequest, input getKeywordForecastInput) (*mcp.CallToolResult, any, error) {
-			return getKeywordForecast(ctx, client, input)
+			return getKeywordForecast(opts.toolContext(ctx, "get_keyword_forecast"), client, input)
+		},
+	)
+
+	mcp.AddTool(srv,
+		&mcp.Tool{
+			Name:        "get_bid_landscape",
+			Description: "Forecast a set of keywords at several max CPC bids using Google Ads Keyword Planner. Returns impressions, clicks, and cost per bid plus the marginal cost per extra click between bids, for finding where higher bids stop paying off.",
+		},
+		func(ctx context.Context, _ *mcp.CallToolRequest, input getBidLandscapeInput) (*mcp.CallToolResult, any, error) {
+			return getBidLandscape(opts.toolContext(ctx, "get_bid_landscape"), client, input)
+		},
+	)
+
+	mcp.AddTool(srv,
+		&mcp.Tool{
+			Name:        "plan_for_budget",
+			Description: "Find the max CPC bid that best fits a budget over a forecast period using Google Ads Keyword Planner forecasts. Returns the bid with projected impressions, clicks, and cost, and lists the keywords to drop when the budget cannot cover them all.",
+		},
+		func(ctx context.Context, _ *mcp.CallToolRequest, input planForBudgetInput) (*mcp.CallToolResult, any, error) {
+			return planForBudget(opts.toolContext(ctx, "plan_for_budget"), client, input)
 		},
 	)
 
@@ -178,38 +345,159 @@ func splitAndTrim(s string) []string {
 }
 
 // generateKeywordIdeasInput is the input schema for the generate_keyword_ideas tool.
-// SeedKeywords and URL both carry ",omitempty" so neither is marked required in the
-// exported JSON schema: the tool only requires that at least one of them be provided,
+// SeedKeywords, URL and Site all carry ",omitempty" so none is marked required in the
+// exported JSON schema: the tool requires seed_keywords and/or url, or site on its own,
 // which is enforced at runtime in generateKeywordIdeas rather than by the schema.
 type generateKeywordIdeasInput struct {
-	SeedKeywords []string `json:"seed_keywords,omitempty" jsonschema:"Seed keywords to generate ideas from (e.g. ['C# tutorial', 'dotnet performance']). At least one of seed_keywords or url must be provided."`
-	URL          string   `json:"url,omitempty"           jsonschema:"A URL to generate ideas from (e.g. 'https://devleader.ca'). At least one of seed_keywords or url must be provided."`
-	Language     string   `json:"language,omitempty"      jsonschema:"Language resource name (e.g. 'languageConstants/1000' for English). Omit to use all languages."`
+	SeedKeywords         []string `json:"seed_keywords,omitempty"          jsonschema:"Seed keywords to generate ideas from (e.g. ['C# tutorial', 'dotnet performance']). At least one of seed_keywords, url or site must be provided."`
+	URL                  string   `json:"url,omitempty"                    jsonschema:"A single page URL to generate ideas from (e.g. 'https://devleader.ca'). At least one of seed_keywords, url or site must be provided."`
+	Site                 string   `json:"site,omitempty"                   jsonschema:"A domain to generate ideas from across the whole site (e.g. 'devleader.ca'). Cannot be combined with url or seed_keywords."`
+	Language             string   `json:"language,omitempty"               jsonschema:"Language resource name (e.g. 'languageConstants/1000' for English). Omit to use all languages."`
+	Locations            []string `json:"locations,omitempty"              jsonschema:"Locations to target, as geo target constant resource names or numeric IDs (e.g. ['geoTargetConstants/2840'] or ['2840'] for the United States, '2826' for the United Kingdom, '2124' for Canada). Omit to use all locations."`
+	Network              string   `json:"network,omitempty"                jsonschema:"Keyword plan network: 'GOOGLE_SEARCH' or 'GOOGLE_SEARCH_AND_PARTNERS'. Defaults to 'GOOGLE_SEARCH_AND_PARTNERS' if omitted."`
+	IncludeAdultKeywords bool     `json:"include_adult_keywords,omitempty" jsonschema:"Include adult keywords in the results. Defaults to false."`
+	BreakdownByDevice    bool     `json:"breakdown_by_device,omitempty"    jsonschema:"Also return search counts split by device (mobile, desktop, tablet) across the returned ideas. Left out when more than 20 seed keywords are split across several requests. Defaults to false."`
+	IncludeConcepts      bool     `json:"include_concepts,omitempty"       jsonschema:"Annotate each idea with the topical concepts and brand/non-brand concept groups Google assigns to it. Defaults to false."`
+	GroupByConcept       bool     `json:"group_by_concept,omitempty"       jsonschema:"Also return conceptGroups: the idea keywords bucketed by concept group with combined search volume, for reasoning about themes. Implies include_concepts."`
+	PageSize             int      `json:"page_size,omitempty"              jsonschema:"Number of ideas to return per page (1-10000). Omit or 0 to use the API default."`
+	PageToken            string   `json:"page_token,omitempty"             jsonschema:"Page token from a previous response's nextPageToken, to fetch the next page of ideas."`
+	MaxIdeas             int      `json:"max_ideas,omitempty"              jsonschema:"When set, keep fetching pages until this many ideas are collected or no more pages remain. Omit or 0 to fetch a single page."`
 }
 
 // getHistoricalMetricsInput is the input schema for the get_historical_metrics tool.
 type getHistoricalMetricsInput struct {
-	Keywords []string `json:"keywords" jsonschema:"List of keywords to get historical search metrics for (e.g. ['dependency injection', 'SOLID principles'])."`
+	Keywords          []string `json:"keywords"                      jsonschema:"List of keywords to get historical search metrics for (e.g. ['dependency injection', 'SOLID principles'])."`
+	StartMonth        string   `json:"start_month,omitempty"         jsonschema:"First month of the range as YYYY-MM (e.g. '2023-01'), at most 4 years ago. Must be given together with end_month. Omit both for the trailing 12 months."`
+	EndMonth          string   `json:"end_month,omitempty"           jsonschema:"Last month of the range as YYYY-MM (e.g. '2023-12'), no later than the current month. Must be given together with start_month."`
+	IncludeAverageCPC bool     `json:"include_average_cpc,omitempty" jsonschema:"Include the average cost per click (averageCpcMicros) for each keyword. Defaults to false."`
+	BreakdownByDevice bool     `json:"breakdown_by_device,omitempty" jsonschema:"Also return search counts split by device (mobile, desktop, tablet) across the requested keywords. Defaults to false."`
 }
 
 // getKeywordForecastInput is the input schema for the get_keyword_forecast tool.
+// Keywords, KeywordSpecs and AdGroups all carry ",omitempty"; the keywords are given
+// either at the top level or in ad groups, which is enforced at runtime in
+// buildForecastRequest.
 type getKeywordForecastInput struct {
-	Keywords     []string `json:"keywords"                 jsonschema:"List of keywords to forecast performance for."`
-	MaxCPCMicros int64    `json:"max_cpc_micros,omitempty" jsonschema:"Maximum CPC bid in micros (1,000,000 = $1.00). Defaults to 1,000,000 if omitted or 0."`
-	ForecastDays int      `json:"forecast_days,omitempty"  jsonschema:"Number of days to forecast. Defaults to 30 if omitted or 0."`
+	Keywords         []string               `json:"keywords,omitempty"          jsonschema:"List of keywords to forecast performance for, using match_type and max_cpc_micros. At least one of keywords, keyword_specs or ad_groups must be provided."`
+	KeywordSpecs     []forecastKeywordSpec  `json:"keyword_specs,omitempty"     jsonschema:"Keywords with their own match type and bid, for forecasting each keyword differently. At least one of keywords, keyword_specs or ad_groups must be provided."`
+	AdGroups         []forecastAdGroupInput `json:"ad_groups,omitempty"         jsonschema:"Themed ad groups, each with its own keywords, default bid and negatives. Results are keyed by ad group name. Cannot be combined with top-level keywords or keyword_specs."`
+	MatchType        string                 `json:"match_type,omitempty"        jsonschema:"Default match type: 'EXACT', 'PHRASE' or 'BROAD'. Applies to keywords and to keyword_specs entries without their own match_type. Defaults to 'BROAD'."`
+	MaxCPCMicros     int64                  `json:"max_cpc_micros,omitempty"    jsonschema:"Maximum CPC bid in micros (1,000,000 = $1.00). Defaults to 1,000,000 if omitted or 0."`
+	ForecastDays     int                    `json:"forecast_days,omitempty"     jsonschema:"Number of days to forecast. Defaults to 30 if omitted or 0. Cannot be combined with end_date."`
+	StartDate        string                 `json:"start_date,omitempty"        jsonschema:"First day of the forecast as YYYY-MM-DD (e.g. '2025-11-24'), today or later. Defaults to today."`
+	EndDate          string                 `json:"end_date,omitempty"          jsonschema:"Last day of the forecast as YYYY-MM-DD (e.g. '2025-12-31'), at most one year from today. Defaults to forecast_days after start_date."`
+	Locations        []string               `json:"locations,omitempty"         jsonschema:"Locations to target, as geo target constant resource names or numeric IDs (e.g. ['2840'] for the United States). Omit to forecast worldwide."`
+	Languages        []string               `json:"languages,omitempty"         jsonschema:"Languages to target, as language constant resource names or numeric IDs (e.g. ['languageConstants/1000'] or ['1000'] for English). Omit to forecast all languages."`
+	Network          string                 `json:"network,omitempty"           jsonschema:"Keyword plan network: 'GOOGLE_SEARCH' or 'GOOGLE_SEARCH_AND_PARTNERS'. Defaults to 'GOOGLE_SEARCH_AND_PARTNERS' if omitted."`
+	BiddingStrategy  *biddingStrategyInput  `json:"bidding_strategy,omitempty"  jsonschema:"Bidding strategy for the forecast campaign. Omit for manual CPC at max_cpc_micros."`
+	NegativeKeywords []string               `json:"negative_keywords,omitempty" jsonschema:"Campaign-level negative keywords (broad match) to exclude from the forecast (e.g. ['free', 'jobs'])."`
+}
+
+// getBidLandscapeInput is the input schema for the get_bid_landscape tool. The bids
+// are given either as BidsMicros or as the MinCPCMicros..MaxCPCMicros range, which is
+// enforced at runtime in getBidLandscape.
+type getBidLandscapeInput struct {
+	Keywords         []string               `json:"keywords,omitempty"          jsonschema:"List of keywords to forecast, using match_type. At least one of keywords, keyword_specs or ad_groups must be provided."`
+	KeywordSpecs     []forecastKeywordSpec  `json:"keyword_specs,omitempty"     jsonschema:"Keywords with their own match type. A max_cpc_micros set here stays fixed across the sweep."`
+	AdGroups         []forecastAdGroupInput `json:"ad_groups,omitempty"         jsonschema:"Themed ad groups, as in get_keyword_forecast. An ad group max_cpc_micros stays fixed across the sweep. Cannot be combined with top-level keywords or keyword_specs."`
+	MatchType        string                 `json:"match_type,omitempty"        jsonschema:"Default match type: 'EXACT', 'PHRASE' or 'BROAD'. Defaults to 'BROAD'."`
+	BidsMicros       []int64                `json:"bids_micros,omitempty"       jsonschema:"Max CPC bids to forecast, in micros (1,000,000 = $1.00), e.g. [500000, 1000000, 2000000]. Up to 20 bids. Cannot be combined with min_cpc_micros/max_cpc_micros."`
+	MinCPCMicros     int64                  `json:"min_cpc_micros,omitempty"    jsonschema:"Lowest bid of an evenly spaced sweep, in micros. Must be given together with max_cpc_micros."`
+	MaxCPCMicros     int64                  `json:"max_cpc_micros,omitempty"    jsonschema:"Highest bid of an evenly spaced sweep, in micros. Must be given together with min_cpc_micros."`
+	Steps            int                    `json:"steps,omitempty"             jsonschema:"Number of bids in the min_cpc_micros..max_cpc_micros sweep (2-20). Defaults to 5."`
+	ForecastDays     int                    `json:"forecast_days,omitempty"     jsonschema:"Number of days to forecast. Defaults to 30 if omitted or 0. Cannot be combined with end_date."`
+	StartDate        string                 `json:"start_date,omitempty"        jsonschema:"First day of the forecast as YYYY-MM-DD, today or later. Defaults to today."`
+	EndDate          string                 `json:"end_date,omitempty"          jsonschema:"Last day of the forecast as YYYY-MM-DD, at most one year from today. Defaults to forecast_days after start_date."`
+	Locations        []string               `json:"locations,omitempty"         jsonschema:"Locations to target, as geo target constant resource names or numeric IDs (e.g. ['2840'] for the United States). Omit to forecast worldwide."`
+	Languages        []string               `json:"languages,omitempty"         jsonschema:"Languages to target, as language constant resource names or numeric IDs (e.g. ['1000'] for English). Omit to forecast all languages."`
+	Network          string                 `json:"network,omitempty"           jsonschema:"Keyword plan network: 'GOOGLE_SEARCH' or 'GOOGLE_SEARCH_AND_PARTNERS'. Defaults to 'GOOGLE_SEARCH_AND_PARTNERS' if omitted."`
+	NegativeKeywords []string               `json:"negative_keywords,omitempty" jsonschema:"Campaign-level negative keywords (broad match) to exclude from the forecast."`
+}
+
+// planForBudgetInput is the input schema for the plan_for_budget tool.
+type planForBudgetInput struct {
+	Keywords         []string               `json:"keywords,omitempty"          jsonschema:"List of keywords to plan for, using match_type. At least one of keywords, keyword_specs or ad_groups must be provided."`
+	KeywordSpecs     []forecastKeywordSpec  `json:"keyword_specs,omitempty"     jsonschema:"Keywords with their own match type. A max_cpc_micros set here stays fixed while the campaign bid is searched."`
+	AdGroups         []forecastAdGroupInput `json:"ad_groups,omitempty"         jsonschema:"Themed ad groups, as in get_keyword_forecast. An ad group max_cpc_micros stays fixed while the campaign bid is searched. Cannot be combined with top-level keywords or keyword_specs."`
+	MatchType        string                 `json:"match_type,omitempty"        jsonschema:"Default match type: 'EXACT', 'PHRASE' or 'BROAD'. Defaults to 'BROAD'."`
+	BudgetMicros     int64                  `json:"budget_micros"               jsonschema:"Total budget for the whole forecast period in micros (1,000,000 = $1.00), e.g. 500000000 for $500."`
+	MinCPCMicros     int64                  `json:"min_cpc_micros,omitempty"    jsonschema:"Lowest bid to consider, in micros. Defaults to 10,000 ($0.01)."`
+	MaxCPCMicros     int64                  `json:"max_cpc_micros,omitempty"    jsonschema:"Highest bid to consider, in micros. Defaults to 50,000,000 ($50.00)."`
+	ForecastDays     int                    `json:"forecast_days,omitempty"     jsonschema:"Number of days the budget covers. Defaults to 30 if omitted or 0. Cannot be combined with end_date."`
+	StartDate        string                 `json:"start_date,omitempty"        jsonschema:"First day of the forecast as YYYY-MM-DD, today or later. Defaults to today."`
+	EndDate          string                 `json:"end_date,omitempty"          jsonschema:"Last day of the forecast as YYYY-MM-DD, at most one year from today. Defaults to forecast_days after start_date."`
+	Locations        []string               `json:"locations,omitempty"         jsonschema:"Locations to target, as geo target constant resource names or numeric IDs (e.g. ['2840'] for the United States). Omit to forecast worldwide."`
+	Languages        []string               `json:"languages,omitempty"         jsonschema:"Languages to target, as language constant resource names or numeric IDs (e.g. ['1000'] for English). Omit to forecast all languages."`
+	Network          string                 `json:"network,omitempty"           jsonschema:"Keyword plan network: 'GOOGLE_SEARCH' or 'GOOGLE_SEARCH_AND_PARTNERS'. Defaults to 'GOOGLE_SEARCH_AND_PARTNERS' if omitted."`
+	NegativeKeywords []string               `json:"negative_keywords,omitempty" jsonschema:"Campaign-level negative keywords (broad match) to exclude from the forecast."`
+}
+
+// forecastAdGroupInput is one entry of get_keyword_forecast's ad_groups argument.
+type forecastAdGroupInput struct {
+	Name             string                `json:"name"                        jsonschema:"Unique ad group name, used to key its results."`
+	Keywords         []string              `json:"keywords,omitempty"          jsonschema:"Keywords in this ad group, using the top-level match_type."`
+	KeywordSpecs     []forecastKeywordSpec `json:"keyword_specs,omitempty"     jsonschema:"Keywords in this ad group with their own match type and bid."`
+	MaxCPCMicros     int64                 `json:"max_cpc_micros,omitempty"    jsonschema:"Default max CPC bid in micros for this ad group. Defaults to the top-level max_cpc_micros."`
+	NegativeKeywords []string              `json:"negative_keywords,omitempty" jsonschema:"Negative keywords (broad match) for this ad group only."`
+}
+
+// biddingStrategyInput is get_keyword_forecast's bidding_strategy argument.
+type biddingStrategyInput struct {
+	Type                string `json:"type"                             jsonschema:"'MANUAL_CPC', 'MAXIMIZE_CLICKS' or 'MAXIMIZE_CONVERSIONS'."`
+	DailyBudgetMicros   int64  `json:"daily_budget_micros,omitempty"    jsonschema:"Average daily budget in micros (1,000,000 = $1.00). Required for MAXIMIZE_CLICKS and MAXIMIZE_CONVERSIONS; optional for MANUAL_CPC."`
+	MaxCPCCeilingMicros int64  `json:"max_cpc_ceiling_micros,omitempty" jsonschema:"Optional CPC bid ceiling in micros for MAXIMIZE_CLICKS."`
+}
+
+// forecastKeywordSpec is one entry of get_keyword_forecast's keyword_specs argument.
+type forecastKeywordSpec struct {
+	Text         string `json:"text"                     jsonschema:"The keyword text."`
+	MatchType    string `json:"match_type,omitempty"     jsonschema:"Match type for this keyword: 'EXACT', 'PHRASE' or 'BROAD'. Defaults to the top-level match_type."`
+	MaxCPCMicros int64  `json:"max_cpc_micros,omitempty" jsonschema:"Max CPC bid in micros for this keyword. Defaults to the top-level max_cpc_micros."`
 }
 
 func generateKeywordIdeas(ctx context.Context, client *keywordplanner.Client, input generateKeywordIdeasInput) (*mcp.CallToolResult, any, error) {
-	if len(input.SeedKeywords) == 0 && input.URL == "" {
-		errResult := map[string]string{"error": "at least one of seed_keywords or url must be provided"}
-		b, _ := json.Marshal(errResult)
-		return &mcp.CallToolResult{Content: []mcp.Content{&mcp.TextContent{Text: string(b)}}}, nil, nil
+	if len(input.SeedKeywords) == 0 && input.URL == "" && input.Site == "" {
+		return errorResult("at least one of seed_keywords, url or site must be provided"), nil, nil
+	}
+	if input.Site != "" && input.URL != "" {
+		return errorResult("site and url are mutually exclusive; use site for a whole domain or url for a single page"), nil, nil
+	}
+	if input.Site != "" && len(input.SeedKeywords) > 0 {
+		return errorResult("site cannot be combined with seed_keywords"), nil, nil
+	}
+	locations, err := keywordplanner.NormalizeGeoTargetConstants(input.Locations)
+	if err != nil {
+		return errorResult(err.Error()), nil, nil
 	}
-	result, err := client.GenerateKeywordIdeas(ctx, input.SeedKeywords, input.URL, input.Language)
+	network, err := keywordplanner.NormalizeNetwork(input.Network)
 	if err != nil {
-		errResult := map[string]string{"error": fmt.Sprintf("generating keyword ideas: %v", err)}
-		b, _ := json.Marshal(errResult)
-		return &mcp.CallToolResult{Content: []mcp.Content{&mcp.TextContent{Text: string(b)}}}, nil, nil
+		return errorResult(err.Error()), nil, nil
+	}
+	if input.PageSize < 0 || input.PageSize > keywordplanner.MaxKeywordIdeasPageSize {
+		return errorResult(fmt.Sprintf("page_size must be between 1 and %d", keywordplanner.MaxKeywordIdeasPageSize)), nil, nil
+	}
+	if input.MaxIdeas < 0 {
+		return errorResult("max_ideas must not be negative"), nil, nil
+	}
+	result, err := client.GenerateKeywordIdeas(ctx, keywordplanner.KeywordIdeasRequest{
+		SeedKeywords:         input.SeedKeywords,
+		URL:                  input.URL,
+		Site:                 input.Site,
+		Language:             input.Language,
+		Locations:            locations,
+		Network:              network,
+		IncludeAdultKeywords: input.IncludeAdultKeywords,
+		BreakdownByDevice:    input.BreakdownByDevice,
+		IncludeConcepts:      input.IncludeConcepts || input.GroupByConcept,
+		PageSize:             input.PageSize,
+		PageToken:            input.PageToken,
+		MaxIdeas:             input.MaxIdeas,
+	})
+	if err != nil {
+		return upstreamErrorResult("generating keyword ideas", err), nil, nil
+	}
+	if input.GroupByConcept {
+		result.ConceptGroups = keywordplanner.GroupIdeasByConcept(result.Ideas)
 	}
 	b, err := json.Marshal(result)
 	if err != nil {
@@ -219,11 +507,26 @@ func generateKeywordIdeas(ctx context.Context, client *keywordplanner.Client, in
 }
 
 func getHistoricalMetrics(ctx context.Context, client *keywordplanner.Client, input getHistoricalMetricsInput) (*mcp.CallToolResult, any, error) {
-	result, err := client.GetHistoricalMetrics(ctx, input.Keywords)
+	req := keywordplanner.HistoricalMetricsRequest{
+		Keywords:          input.Keywords,
+		IncludeAverageCPC: input.IncludeAverageCPC,
+		BreakdownByDevice: input.BreakdownByDevice,
+	}
+	if (input.StartMonth == "") != (input.EndMonth == "") {
+		return errorResult("start_month and end_month must be provided together"), nil, nil
+	}
+	if input.StartMonth != "" {
+		var err error
+		if req.StartMonth, err = keywordplanner.ParseYearMonth(input.StartMonth); err != nil {
+			return errorResult(fmt.Sprintf("start_month: %v", err)), nil, nil
+		}
+		if req.EndMonth, err = keywordplanner.ParseYearMonth(input.EndMonth); err != nil {
+			return errorResult(fmt.Sprintf("end_month: %v", err)), nil, nil
+		}
+	}
+	result, err := client.GetHistoricalMetrics(ctx, req)
 	if err != nil {
-		errResult := map[string]string{"error": fmt.Sprintf("getting historical metrics: %v", err)}
-		b, _ := json.Marshal(errResult)
-		return &mcp.CallToolResult{Content: []mcp.Content{&mcp.TextContent{Text: string(b)}}}, nil, nil
+		return upstreamErrorResult("getting historical metrics", err), nil, nil
 	}
 	b, err := json.Marshal(result)
 	if err != nil {
@@ -233,11 +536,13 @@ func getHistoricalMetrics(ctx context.Context, client *keywordplanner.Client, in
 }
 
 func getKeywordForecast(ctx context.Context, client *keywordplanner.Client, input getKeywordForecastInput) (*mcp.CallToolResult, any, error) {
-	result, err := client.GetKeywordForecast(ctx, input.Keywords, input.MaxCPCMicros, input.ForecastDays)
+	req, err := buildForecastRequest(input)
+	if err != nil {
+		return errorResult(err.Error()), nil, nil
+	}
+	result, err := client.GetKeywordForecast(ctx, req)
 	if err != nil {
-		errResult := map[string]string{"error": fmt.Sprintf("getting keyword forecast: %v", err)}
-		b, _ := json.Marshal(errResult)
-		return &mcp.CallToolResult{Content: []mcp.Content{&mcp.TextContent{Text: string(b)}}}, nil, nil
+		return upstreamErrorResult("getting keyword forecast", err), nil, nil
 	}
 	b, err := json.Marshal(result)
 	if err != nil {
@@ -245,3 +550,240 @@ func getKeywordForecast(ctx context.Context, client *keywordplanner.Client, inpu
 	}
 	return &mcp.CallToolResult{Content: []mcp.Content{&mcp.TextContent{Text: string(b)}}}, nil, nil
 }
+
+func getBidLandscape(ctx context.Context, client *keywordplanner.Client, input getBidLandscapeInput) (*mcp.CallToolResult, any, error) {
+	bids, err := landscapeBids(input)
+	if err != nil {
+		return errorResult(err.Error()), nil, nil
+	}
+	forecast, err := buildForecastRequest(getKeywordForecastInput{
+		Keywords:         input.Keywords,
+		KeywordSpecs:     input.KeywordSpecs,
+		AdGroups:         input.AdGroups,
+		MatchType:        input.MatchType,
+		ForecastDays:     input.ForecastDays,
+		StartDate:        input.StartDate,
+		EndDate:          input.EndDate,
+		Locations:        input.Locations,
+		Languages:        input.Languages,
+		Network:          input.Network,
+		NegativeKeywords: input.NegativeKeywords,
+	})
+	if err != nil {
+		return errorResult(err.Error()), nil, nil
+	}
+	result, err := client.GetBidLandscape(ctx, keywordplanner.BidLandscapeRequest{
+		Forecast:   forecast,
+		BidsMicros: bids,
+	})
+	if err != nil {
+		return upstreamErrorResult("getting bid landscape", err), nil, nil
+	}
+	b, err := json.Marshal(result)
+	if err != nil {
+		return nil, nil, fmt.Errorf("marshalling result: %w", err)
+	}
+	return &mcp.CallToolResult{Content: []mcp.Content{&mcp.TextContent{Text: string(b)}}}, nil, nil
+}
+
+func planForBudget(ctx context.Context, client *keywordplanner.Client, input planForBudgetInput) (*mcp.CallToolResult, any, error) {
+	if input.BudgetMicros <= 0 {
+		return errorResult("budget_micros must be positive"), nil, nil
+	}
+	if input.MinCPCMicros < 0 || input.MaxCPCMicros < 0 {
+		return errorResult("min_cpc_micros and max_cpc_micros must not be negative"), nil, nil
+	}
+	if input.MinCPCMicros > 0 && input.MaxCPCMicros > 0 && input.MaxCPCMicros < input.MinCPCMicros {
+		return errorResult("max_cpc_micros must not be less than min_cpc_micros"), nil, nil
+	}
+	forecast, err := buildForecastRequest(getKeywordForecastInput{
+		Keywords:         input.Keywords,
+		KeywordSpecs:     input.KeywordSpecs,
+		AdGroups:         input.AdGroups,
+		MatchType:        input.MatchType,
+		ForecastDays:     input.ForecastDays,
+		StartDate:        input.StartDate,
+		EndDate:          input.EndDate,
+		Locations:        input.Locations,
+		Languages:        input.Languages,
+		Network:          input.Network,
+		NegativeKeywords: input.NegativeKeywords,
+	})
+	if err != nil {
+		return errorResult(err.Error()), nil, nil
+	}
+	result, err := client.PlanForBudget(ctx, keywordplanner.BudgetPlanRequest{
+		Forecast:     forecast,
+		BudgetMicros: input.BudgetMicros,
+		MinCPCMicros: input.MinCPCMicros,
+		MaxCPCMicros: input.MaxCPCMicros,
+	})
+	if err != nil {
+		return upstreamErrorResult("planning for budget", err), nil, nil
+	}
+	b, err := json.Marshal(result)
+	if err != nil {
+		return nil, nil, fmt.Errorf("marshalling result: %w", err)
+	}
+	return &mcp.CallToolResult{Content: []mcp.Content{&mcp.TextContent{Text: string(b)}}}, nil, nil
+}
+
+// landscapeBids returns the bids a get_bid_landscape call sweeps: the explicit
+// bids_micros list, or the evenly spaced min_cpc_micros..max_cpc_micros range.
+func landscapeBids(input getBidLandscapeInput) ([]int64, error) {
+	hasRange := input.MinCPCMicros != 0 || input.MaxCPCMicros != 0 || input.Steps != 0
+	if len(input.BidsMicros) > 0 {
+		if hasRange {
+			return nil, errors.New("bids_micros cannot be combined with min_cpc_micros, max_cpc_micros or steps")
+		}
+		if len(input.BidsMicros) > keywordplanner.MaxBidLandscapePoints {
+			return nil, fmt.Errorf("bids_micros must contain at most %d bids", keywordplanner.MaxBidLandscapePoints)
+		}
+		for _, bid := range input.BidsMicros {
+			if bid <= 0 {
+				return nil, fmt.Errorf("bids_micros must be positive, got %d", bid)
+			}
+		}
+		return input.BidsMicros, nil
+	}
+	if input.MinCPCMicros == 0 || input.MaxCPCMicros == 0 {
+		return nil, errors.New("either bids_micros or both min_cpc_micros and max_cpc_micros must be provided")
+	}
+	steps := input.Steps
+	if steps == 0 {
+		steps = 5
+	}
+	return keywordplanner.LinearBids(input.MinCPCMicros, input.MaxCPCMicros, steps)
+}
+
+// buildForecastRequest validates a get_keyword_forecast input and converts it to a
+// client request, so invalid arguments are reported before any API call is made.
+func buildForecastRequest(input getKeywordForecastInput) (keywordplanner.ForecastRequest, error) {
+	hasKeywords := len(input.Keywords) > 0 || len(input.KeywordSpecs) > 0
+	if !hasKeywords && len(input.AdGroups) == 0 {
+		return keywordplanner.ForecastRequest{}, errors.New("at least one of keywords, keyword_specs or ad_groups must be provided")
+	}
+	if hasKeywords && len(input.AdGroups) > 0 {
+		return keywordplanner.ForecastRequest{}, errors.New("ad_groups cannot be combined with top-level keywords or keyword_specs; put those keywords in an ad group")
+	}
+	if _, err := keywordplanner.NormalizeMatchType(input.MatchType); err != nil {
+		return keywordplanner.ForecastRequest{}, err
+	}
+	locations, err := keywordplanner.NormalizeGeoTargetConstants(input.Locations)
+	if err != nil {
+		return keywordplanner.ForecastRequest{}, err
+	}
+	languages, err := keywordplanner.NormalizeLanguageConstants(input.Languages)
+	if err != nil {
+		return keywordplanner.ForecastRequest{}, err
+	}
+	network, err := keywordplanner.NormalizeNetwork(input.Network)
+	if err != nil {
+		return keywordplanner.ForecastRequest{}, err
+	}
+	var strategy keywordplanner.BiddingStrategy
+	if input.BiddingStrategy != nil {
+		strategy, err = keywordplanner.NormalizeBiddingStrategy(keywordplanner.BiddingStrategy{
+			Type:                input.BiddingStrategy.Type,
+			DailyBudgetMicros:   input.BiddingStrategy.DailyBudgetMicros,
+			MaxCPCCeilingMicros: input.BiddingStrategy.MaxCPCCeilingMicros,
+		})
+		if err != nil {
+			return keywordplanner.ForecastRequest{}, err
+		}
+	}
+	var startDate, endDate time.Time
+	if input.StartDate != "" {
+		if startDate, err = keywordplanner.ParseDate(input.StartDate); err != nil {
+			return keywordplanner.ForecastRequest{}, fmt.Errorf("start_date: %w", err)
+		}
+	}
+	if input.EndDate != "" {
+		if input.ForecastDays > 0 {
+			return keywordplanner.ForecastRequest{}, errors.New("forecast_days and end_date are mutually exclusive")
+		}
+		if endDate, err = keywordplanner.ParseDate(input.EndDate); err != nil {
+			return keywordplanner.ForecastRequest{}, fmt.Errorf("end_date: %w", err)
+		}
+	}
+	keywords, err := toForecastKeywords(input.Keywords, input.KeywordSpecs)
+	if err != nil {
+		return keywordplanner.ForecastRequest{}, err
+	}
+
+	adGroups := make([]keywordplanner.ForecastAdGroup, 0, len(input.AdGroups))
+	seenNames := make(map[string]bool, len(input.AdGroups))
+	for _, group := range input.AdGroups {
+		name := strings.TrimSpace(group.Name)
+		if name == "" {
+			return keywordplanner.ForecastRequest{}, errors.New("every ad_groups entry must have a name")
+		}
+		if seenNames[name] {
+			return keywordplanner.ForecastRequest{}, fmt.Errorf("duplicate ad group name %q", name)
+		}
+		seenNames[name] = true
+		if group.MaxCPCMicros < 0 {
+			return keywordplanner.ForecastRequest{}, fmt.Errorf("ad group %q: max_cpc_micros must not be negative", name)
+		}
+		groupKeywords, err := toForecastKeywords(group.Keywords, group.KeywordSpecs)
+		if err != nil {
+			return keywordplanner.ForecastRequest{}, fmt.Errorf("ad group %q: %w", name, err)
+		}
+		if len(groupKeywords) == 0 {
+			return keywordplanner.ForecastRequest{}, fmt.Errorf("ad group %q: at least one of keywords or keyword_specs must be provided", name)
+		}
+		adGroups = append(adGroups, keywordplanner.ForecastAdGroup{
+			Name:             name,
+			Keywords:         groupKeywords,
+			MaxCPCMicros:     group.MaxCPCMicros,
+			NegativeKeywords: group.NegativeKeywords,
+		})
+	}
+
+	if err := keywordplanner.CheckForecastBids(strategy, keywords, adGroups); err != nil {
+		return keywordplanner.ForecastRequest{}, err
+	}
+
+	return keywordplanner.ForecastRequest{
+		Keywords:         keywords,
+		AdGroups:         adGroups,
+		MatchType:        input.MatchType,
+		MaxCPCMicros:     input.MaxCPCMicros,
+		ForecastDays:     input.ForecastDays,
+		StartDate:        startDate,
+		EndDate:          endDate,
+		Locations:        locations,
+		Languages:        languages,
+		Network:          network,
+		NegativeKeywords: input.NegativeKeywords,
+		BiddingStrategy:  strategy,
+	}, nil
+}
+
+// toForecastKeywords merges plain keywords and keyword_specs entries into one list,
+// validating each spec's match type and bid.
+func toForecastKeywords(plain []string, specs []forecastKeywordSpec) ([]keywordplanner.ForecastKeyword, error) {
+	keywords := make([]keywordplanner.ForecastKeyword, 0, len(plain)+len(specs))
+	for _, kw := range plain {
+		keywords = append(keywords, keywordplanner.ForecastKeyword{Text: kw})
+	}
+	for _, spec := range specs {
+		if strings.TrimSpace(spec.Text) == "" {
+			return nil, errors.New("keyword_specs entries must have a non-empty text")
+		}
+		if spec.MatchType != "" {
+			if _, err := keywordplanner.NormalizeMatchType(spec.MatchType); err != nil {
+				return nil, fmt.Errorf("keyword %q: %w", spec.Text, err)
+			}
+		}
+		if spec.MaxCPCMicros < 0 {
+			return nil, fmt.Errorf("keyword %q: max_cpc_micros must not be negative", spec.Text)
+		}
+		keywords = append(keywords, keywordplanner.ForecastKeyword{
+			Text:         spec.Text,
+			MatchType:    spec.MatchType,
+			MaxCPCMicros: spec.MaxCPCMicros,
+		})
+	}
+	return keywords, nil
+}
@@ -7,7 +7,9 @@ import (
 	"net/http/httptest"
 	"slices"
 	"strings"
+	"sync/atomic"
 	"testing"
+	"time"
 
 	"github.com/google/jsonschema-go/jsonschema"
 	"github.com/modelcontextprotocol/go-sdk/mcp"
@@ -21,7 +23,7 @@ func TestNewServer_RegistersTools(t *testing.T) {
 	t.Parallel()
 
 	client := keywordplanner.NewClient("token", "id", "secret", "refresh", "123", "")
-	srv := newServer(client)
+	srv := newServer(client, serverOptions{})
 
 	ctx := context.Background()
 	clientTransport, serverTransport := mcp.NewInMemoryTransports()
@@ -48,7 +50,7 @@ func TestNewServer_RegistersTools(t *testing.T) {
 	for _, tool := range result.Tools {
 		names = append(names, tool.Name)
 	}
-	for _, want := range []string{"generate_keyword_ideas", "get_historical_metrics", "get_keyword_forecast"} {
+	for _, want := range []string{"generate_keyword_ideas", "get_historical_metrics", "get_keyword_forecast", "get_bid_landscape", "plan_for_budget"} {
 		if !slices.Contains(names, want) {
 			t.Errorf("tool %q not registered; got tools %v", want, names)
 		}
@@ -127,6 +129,40 @@ func TestGetKeywordForecastInput_AllFields_HaveDescriptions(t *testing.T) {
 	}
 }
 
+// TestGetBidLandscapeInput_AllFields_HaveDescriptions confirms every field on
+// getBidLandscapeInput carries a non-empty description.
+func TestGetBidLandscapeInput_AllFields_HaveDescriptions(t *testing.T) {
+	t.Parallel()
+
+	schema, err := jsonschema.For[getBidLandscapeInput](nil)
+	if err != nil {
+		t.Fatalf("schema inference failed: %v", err)
+	}
+
+	for name, prop := range schema.Properties {
+		if prop.Description == "" {
+			t.Errorf("field %q has no description", name)
+		}
+	}
+}
+
+// TestPlanForBudgetInput_AllFields_HaveDescriptions confirms every field on
+// planForBudgetInput carries a non-empty description.
+func TestPlanForBudgetInput_AllFields_HaveDescriptions(t *testing.T) {
+	t.Parallel()
+
+	schema, err := jsonschema.For[planForBudgetInput](nil)
+	if err != nil {
+		t.Fatalf("schema inference failed: %v", err)
+	}
+
+	for name, prop := range schema.Properties {
+		if prop.Description == "" {
+			t.Errorf("field %q has no description", name)
+		}
+	}
+}
+
 // TestGenerateKeywordIdeasInput_SeedKeywordsURLLanguage_AreNotRequired confirms
 // seed_keywords, url, and language are absent from the schema's required list.
 // The tool description states "at least one of seed_keywords or url must be
@@ -141,7 +177,7 @@ func TestGenerateKeywordIdeasInput_SeedKeywordsURLLanguage_AreNotRequired(t *tes
 		t.Fatalf("schema inference failed: %v", err)
 	}
 
-	for _, name := range []string{"seed_keywords", "url", "language"} {
+	for _, name := range []string{"seed_keywords", "url", "site", "language"} {
 		if slices.Contains(schema.Required, name) {
 			t.Errorf("field %q must not be in schema.Required (got %v)", name, schema.Required)
 		}
@@ -205,8 +241,8 @@ func TestGenerateKeywordIdeas_NoSeedKeywordsOrURL_ReturnsValidationError(t *test
 	}
 
 	text := result.Content[0].(*mcp.TextContent).Text
-	if !strings.Contains(text, "at least one of seed_keywords or url must be provided") {
-		t.Errorf("result text = %q, want it to mention the seed_keywords/url requirement", text)
+	if !strings.Contains(text, "at least one of seed_keywords, url or site must be provided") {
+		t.Errorf("result text = %q, want it to mention the seed_keywords/url/site requirement", text)
 	}
 }
 
@@ -235,6 +271,177 @@ func TestGenerateKeywordIdeas_URLOnly_SkipsValidationError(t *testing.T) {
 	}
 }
 
+// TestGenerateKeywordIdeas_SiteWithURL_ReturnsValidationError verifies site and
+// url are rejected together, since they select different seed modes.
+func TestGenerateKeywordIdeas_SiteWithURL_ReturnsValidationError(t *testing.T) {
+	t.Parallel()
+
+	var apiHit bool
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		apiHit = true
+		w.Header().Set("Content-Type", "application/json")
+		_ = json.NewEncoder(w).Encode(map[string]any{"results": []any{}})
+	}))
+	defer srv.Close()
+
+	client := keywordplanner.NewTestClient("dev-token", "123", "", srv.URL, srv.Client())
+
+	result, _, err := generateKeywordIdeas(context.Background(), client, generateKeywordIdeasInput{
+		URL:  "https://example.com/page",
+		Site: "example.com",
+	})
+	if err != nil {
+		t.Fatalf("unexpected protocol error: %v", err)
+	}
+	if apiHit {
+		t.Error("Google Ads API must not be called when both site and url are provided")
+	}
+
+	text := result.Content[0].(*mcp.TextContent).Text
+	if !strings.Contains(text, "mutually exclusive") {
+		t.Errorf("result text = %q, want it to mention site/url exclusivity", text)
+	}
+}
+
+// TestGenerateKeywordIdeas_SiteOnly_SendsSiteSeed verifies a site on its own is
+// accepted and sent as a siteSeed.
+func TestGenerateKeywordIdeas_SiteOnly_SendsSiteSeed(t *testing.T) {
+	t.Parallel()
+
+	var captured map[string]any
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_ = json.NewDecoder(r.Body).Decode(&captured)
+		w.Header().Set("Content-Type", "application/json")
+		_ = json.NewEncoder(w).Encode(map[string]any{"results": []any{}})
+	}))
+	defer srv.Close()
+
+	client := keywordplanner.NewTestClient("dev-token", "123", "", srv.URL, srv.Client())
+
+	result, _, err := generateKeywordIdeas(context.Background(), client, generateKeywordIdeasInput{Site: "example.com"})
+	if err != nil {
+		t.Fatalf("unexpected protocol error: %v", err)
+	}
+
+	seed, ok := captured["siteSeed"].(map[string]any)
+	if !ok || seed["site"] != "example.com" {
+		t.Errorf("siteSeed = %v, want {site: example.com}", captured["siteSeed"])
+	}
+	for _, other := range []string{"keywordSeed", "urlSeed", "keywordAndUrlSeed"} {
+		if _, present := captured[other]; present {
+			t.Errorf("%s must not be sent alongside siteSeed", other)
+		}
+	}
+
+	text := result.Content[0].(*mcp.TextContent).Text
+	if !strings.Contains(text, `"site":"example.com"`) {
+		t.Errorf("result text = %q, want the site echoed", text)
+	}
+}
+
+// TestGenerateKeywordIdeas_InvalidNetwork_ReturnsValidationError verifies an
+// unknown network enum is rejected in the handler before any API call.
+func TestGenerateKeywordIdeas_InvalidNetwork_ReturnsValidationError(t *testing.T) {
+	t.Parallel()
+
+	var apiHit bool
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		apiHit = true
+		w.Header().Set("Content-Type", "application/json")
+		_ = json.NewEncoder(w).Encode(map[string]any{"results": []any{}})
+	}))
+	defer srv.Close()
+
+	client := keywordplanner.NewTestClient("dev-token", "123", "", srv.URL, srv.Client())
+
+	result, _, err := generateKeywordIdeas(context.Background(), client, generateKeywordIdeasInput{
+		SeedKeywords: []string{"x"},
+		Network:      "YOUTUBE",
+	})
+	if err != nil {
+		t.Fatalf("unexpected protocol error: %v", err)
+	}
+	if apiHit {
+		t.Error("Google Ads API must not be called with an invalid network")
+	}
+
+	text := result.Content[0].(*mcp.TextContent).Text
+	if !strings.Contains(text, "invalid network") {
+		t.Errorf("result text = %q, want it to mention %q", text, "invalid network")
+	}
+}
+
+// TestGenerateKeywordIdeas_GroupByConcept_ReturnsConceptGroups verifies that
+// group_by_concept requests annotations and adds conceptGroups to the result.
+func TestGenerateKeywordIdeas_GroupByConcept_ReturnsConceptGroups(t *testing.T) {
+	t.Parallel()
+
+	var captured map[string]any
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_ = json.NewDecoder(r.Body).Decode(&captured)
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{"results": [{
+			"text": "blazor tutorial",
+			"keywordIdeaMetrics": {"avgMonthlySearches": "300"},
+			"keywordAnnotations": {"concepts": [{"name": "tutorial", "conceptGroup": {"name": "Learning", "type": "NON_BRAND"}}]}
+		}]}`))
+	}))
+	defer srv.Close()
+
+	client := keywordplanner.NewTestClient("dev-token", "123", "", srv.URL, srv.Client())
+
+	result, _, err := generateKeywordIdeas(context.Background(), client, generateKeywordIdeasInput{
+		SeedKeywords:   []string{"blazor"},
+		GroupByConcept: true,
+	})
+	if err != nil {
+		t.Fatalf("unexpected protocol error: %v", err)
+	}
+	if _, present := captured["keywordAnnotation"]; !present {
+		t.Error("group_by_concept must request keyword annotations")
+	}
+
+	var parsed keywordplanner.KeywordIdeasResponse
+	if err := json.Unmarshal([]byte(result.Content[0].(*mcp.TextContent).Text), &parsed); err != nil {
+		t.Fatalf("failed to parse result content: %v", err)
+	}
+	if len(parsed.ConceptGroups) != 1 || parsed.ConceptGroups[0].Name != "Learning" {
+		t.Errorf("ConceptGroups = %+v, want one Learning group", parsed.ConceptGroups)
+	}
+}
+
+// TestGenerateKeywordIdeas_InvalidLocation_ReturnsValidationError verifies a
+// malformed location is rejected before any request reaches the Google Ads API.
+func TestGenerateKeywordIdeas_InvalidLocation_ReturnsValidationError(t *testing.T) {
+	t.Parallel()
+
+	var apiHit bool
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		apiHit = true
+		w.Header().Set("Content-Type", "application/json")
+		_ = json.NewEncoder(w).Encode(map[string]any{"results": []any{}})
+	}))
+	defer srv.Close()
+
+	client := keywordplanner.NewTestClient("dev-token", "123", "", srv.URL, srv.Client())
+
+	result, _, err := generateKeywordIdeas(context.Background(), client, generateKeywordIdeasInput{
+		SeedKeywords: []string{"x"},
+		Locations:    []string{"United States"},
+	})
+	if err != nil {
+		t.Fatalf("unexpected protocol error: %v", err)
+	}
+	if apiHit {
+		t.Error("Google Ads API must not be called with an invalid location")
+	}
+
+	text := result.Content[0].(*mcp.TextContent).Text
+	if !strings.Contains(text, "invalid location") {
+		t.Errorf("result text = %q, want it to mention %q", text, "invalid location")
+	}
+}
+
 // TestGenerateKeywordIdeas_APIError_ReturnsErrorContent is a characterization
 // test written ahead of the go-sdk dependency upgrade (issue #10): it pins down
 // the one previously-untested branch of generateKeywordIdeas, confirming a
@@ -309,6 +516,38 @@ func TestGetHistoricalMetrics_Success_ReturnsMarshaledMetrics(t *testing.T) {
 	}
 }
 
+// TestGetHistoricalMetrics_StartMonthWithoutEnd_ReturnsValidationError verifies
+// a half-specified month range is rejected before the API is called.
+func TestGetHistoricalMetrics_StartMonthWithoutEnd_ReturnsValidationError(t *testing.T) {
+	t.Parallel()
+
+	var apiHit bool
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		apiHit = true
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{"metrics": []}`))
+	}))
+	defer srv.Close()
+
+	client := keywordplanner.NewTestClient("dev-token", "123", "", srv.URL, srv.Client())
+
+	result, _, err := getHistoricalMetrics(context.Background(), client, getHistoricalMetricsInput{
+		Keywords:   []string{"x"},
+		StartMonth: "2024-01",
+	})
+	if err != nil {
+		t.Fatalf("unexpected protocol error: %v", err)
+	}
+	if apiHit {
+		t.Error("Google Ads API must not be called with a half-specified range")
+	}
+
+	text := result.Content[0].(*mcp.TextContent).Text
+	if !strings.Contains(text, "must be provided together") {
+		t.Errorf("result text = %q, want it to mention the paired range requirement", text)
+	}
+}
+
 // TestGetHistoricalMetrics_APIError_ReturnsErrorContent verifies a Google Ads
 // API failure surfaces as a tool error result, not a Go/protocol-level error --
 // mirroring the same already-proven pattern for generate_keyword_ideas.
@@ -349,7 +588,7 @@ func TestGetKeywordForecast_Success_ReturnsMarshaledForecast(t *testing.T) {
 			"adGroupForecastMetrics": [{
 				"keywordForecastMetrics": [{
 					"keyword": {"text": "dependency injection", "matchType": "BROAD"},
-					"metrics": {"impressions": 1000, "clicks": 50, "costMicros": 500000, "ctr": 0.05}
+					"metrics": {"impressions": 1000, "clicks": 50, "costMicros": 500000, "clickThroughRate": 0.05}
 				}]
 			}]
 		}`))
@@ -379,6 +618,201 @@ func TestGetKeywordForecast_Success_ReturnsMarshaledForecast(t *testing.T) {
 	}
 }
 
+// TestGetKeywordForecast_InvalidMatchType_ReturnsValidationError verifies an
+// unknown per-keyword match type is rejected before the API is called.
+func TestGetKeywordForecast_InvalidMatchType_ReturnsValidationError(t *testing.T) {
+	t.Parallel()
+
+	var apiHit bool
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		apiHit = true
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{"adGroupForecastMetrics": []}`))
+	}))
+	defer srv.Close()
+
+	client := keywordplanner.NewTestClient("dev-token", "123", "", srv.URL, srv.Client())
+
+	result, _, err := getKeywordForecast(context.Background(), client, getKeywordForecastInput{
+		KeywordSpecs: []forecastKeywordSpec{{Text: "x", MatchType: "FUZZY"}},
+	})
+	if err != nil {
+		t.Fatalf("unexpected protocol error: %v", err)
+	}
+	if apiHit {
+		t.Error("Google Ads API must not be called with an invalid match type")
+	}
+
+	text := result.Content[0].(*mcp.TextContent).Text
+	if !strings.Contains(text, "invalid match type") {
+		t.Errorf("result text = %q, want it to mention %q", text, "invalid match type")
+	}
+}
+
+// TestGetKeywordForecast_NoKeywords_ReturnsValidationError verifies the handler
+// requires keywords, keyword_specs or ad_groups now that none is schema-required.
+func TestGetKeywordForecast_NoKeywords_ReturnsValidationError(t *testing.T) {
+	t.Parallel()
+
+	client := keywordplanner.NewTestClient("dev-token", "123", "", "http://127.0.0.1:0", http.DefaultClient)
+
+	result, _, err := getKeywordForecast(context.Background(), client, getKeywordForecastInput{})
+	if err != nil {
+		t.Fatalf("unexpected protocol error: %v", err)
+	}
+
+	text := result.Content[0].(*mcp.TextContent).Text
+	if !strings.Contains(text, "at least one of keywords, keyword_specs or ad_groups must be provided") {
+		t.Errorf("result text = %q, want it to mention the keywords requirement", text)
+	}
+}
+
+// TestGetKeywordForecast_MaximizeClicksWithoutBudget_ReturnsValidationError
+// verifies bidding strategies are validated before the request is sent.
+func TestGetKeywordForecast_MaximizeClicksWithoutBudget_ReturnsValidationError(t *testing.T) {
+	t.Parallel()
+
+	var apiHit bool
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		apiHit = true
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{"adGroupForecastMetrics": []}`))
+	}))
+	defer srv.Close()
+
+	client := keywordplanner.NewTestClient("dev-token", "123", "", srv.URL, srv.Client())
+
+	result, _, err := getKeywordForecast(context.Background(), client, getKeywordForecastInput{
+		Keywords:        []string{"x"},
+		BiddingStrategy: &biddingStrategyInput{Type: "MAXIMIZE_CLICKS"},
+	})
+	if err != nil {
+		t.Fatalf("unexpected protocol error: %v", err)
+	}
+	if apiHit {
+		t.Error("Google Ads API must not be called with an incomplete bidding strategy")
+	}
+
+	text := result.Content[0].(*mcp.TextContent).Text
+	if !strings.Contains(text, "requires a daily budget") {
+		t.Errorf("result text = %q, want it to mention the daily budget requirement", text)
+	}
+}
+
+// TestGetKeywordForecast_KeywordBidWithAutomatedStrategy_ReturnsValidationError
+// verifies a keyword_specs bid is rejected under an automated bidding strategy.
+func TestGetKeywordForecast_KeywordBidWithAutomatedStrategy_ReturnsValidationError(t *testing.T) {
+	t.Parallel()
+
+	client := keywordplanner.NewTestClient("dev-token", "123", "", "http://127.0.0.1:0", http.DefaultClient)
+
+	result, _, err := getKeywordForecast(context.Background(), client, getKeywordForecastInput{
+		KeywordSpecs:    []forecastKeywordSpec{{Text: "x", MaxCPCMicros: 2_000_000}},
+		BiddingStrategy: &biddingStrategyInput{Type: "MAXIMIZE_CLICKS", DailyBudgetMicros: 50_000_000},
+	})
+	if err != nil {
+		t.Fatalf("unexpected protocol error: %v", err)
+	}
+	text := result.Content[0].(*mcp.TextContent).Text
+	if !strings.Contains(text, "needs MANUAL_CPC bidding") {
+		t.Errorf("result text = %q, want it to mention MANUAL_CPC bidding", text)
+	}
+}
+
+// TestGetKeywordForecast_AdGroupsWithTopLevelKeywords_ReturnsValidationError
+// verifies ad_groups cannot be mixed with top-level keywords.
+func TestGetKeywordForecast_AdGroupsWithTopLevelKeywords_ReturnsValidationError(t *testing.T) {
+	t.Parallel()
+
+	client := keywordplanner.NewTestClient("dev-token", "123", "", "http://127.0.0.1:0", http.DefaultClient)
+
+	result, _, err := getKeywordForecast(context.Background(), client, getKeywordForecastInput{
+		Keywords: []string{"blazor"},
+		AdGroups: []forecastAdGroupInput{{Name: "web", Keywords: []string{"razor"}}},
+	})
+	if err != nil {
+		t.Fatalf("unexpected protocol error: %v", err)
+	}
+
+	text := result.Content[0].(*mcp.TextContent).Text
+	if !strings.Contains(text, "ad_groups cannot be combined") {
+		t.Errorf("result text = %q, want it to mention %q", text, "ad_groups cannot be combined")
+	}
+}
+
+// TestGetKeywordForecast_AdGroups_ReturnsMetricsByName verifies ad_groups are
+// forwarded and the response is keyed by ad group name.
+func TestGetKeywordForecast_AdGroups_ReturnsMetricsByName(t *testing.T) {
+	t.Parallel()
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{"adGroupForecastMetrics": [
+			{"keywordForecastMetrics": [{"keyword": {"text": "blazor"}, "metrics": {"clicks": 4}}]},
+			{"keywordForecastMetrics": [{"keyword": {"text": "maui"}, "metrics": {"clicks": 6}}]}
+		]}`))
+	}))
+	defer srv.Close()
+
+	client := keywordplanner.NewTestClient("dev-token", "123", "", srv.URL, srv.Client())
+
+	result, _, err := getKeywordForecast(context.Background(), client, getKeywordForecastInput{
+		AdGroups: []forecastAdGroupInput{
+			{Name: "web", Keywords: []string{"blazor"}},
+			{Name: "mobile", KeywordSpecs: []forecastKeywordSpec{{Text: "maui", MatchType: "EXACT"}}},
+		},
+	})
+	if err != nil {
+		t.Fatalf("unexpected protocol error: %v", err)
+	}
+
+	var resp keywordplanner.ForecastResponse
+	if err := json.Unmarshal([]byte(result.Content[0].(*mcp.TextContent).Text), &resp); err != nil {
+		t.Fatalf("unmarshal result: %v", err)
+	}
+	if resp.AdGroups["web"].Clicks != 4 || resp.AdGroups["mobile"].Clicks != 6 {
+		t.Errorf("AdGroups = %+v, want web=4 and mobile=6 clicks", resp.AdGroups)
+	}
+}
+
+// TestGetKeywordForecast_InvalidDates_ReturnsValidationError verifies malformed
+// dates and end_date combined with forecast_days are rejected before any API call.
+func TestGetKeywordForecast_InvalidDates_ReturnsValidationError(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name  string
+		input getKeywordForecastInput
+		want  string
+	}{
+		{
+			name:  "malformed start",
+			input: getKeywordForecastInput{Keywords: []string{"go"}, StartDate: "24/11/2025"},
+			want:  "start_date: invalid date",
+		},
+		{
+			name:  "end with days",
+			input: getKeywordForecastInput{Keywords: []string{"go"}, EndDate: "2025-12-31", ForecastDays: 14},
+			want:  "forecast_days and end_date are mutually exclusive",
+		},
+	}
+
+	client := keywordplanner.NewTestClient("dev-token", "123", "", "http://127.0.0.1:0", http.DefaultClient)
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			t.Parallel()
+			result, _, err := getKeywordForecast(context.Background(), client, test.input)
+			if err != nil {
+				t.Fatalf("unexpected protocol error: %v", err)
+			}
+			text := result.Content[0].(*mcp.TextContent).Text
+			if !strings.Contains(text, test.want) {
+				t.Errorf("result text = %q, want it to mention %q", text, test.want)
+			}
+		})
+	}
+}
+
 // TestGetKeywordForecast_APIError_ReturnsErrorContent verifies a Google Ads API
 // failure surfaces as a tool error result, not a Go/protocol-level error.
 func TestGetKeywordForecast_APIError_ReturnsErrorContent(t *testing.T) {
@@ -403,6 +837,225 @@ func TestGetKeywordForecast_APIError_ReturnsErrorContent(t *testing.T) {
 	}
 }
 
+// TestGetBidLandscape_Range_ReturnsPointPerBid verifies a min..max sweep forecasts
+// one point per step.
+func TestGetBidLandscape_Range_ReturnsPointPerBid(t *testing.T) {
+	t.Parallel()
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{"campaignForecastMetrics": {"clicks": 10, "costMicros": 5000000}}`))
+	}))
+	defer srv.Close()
+
+	client := keywordplanner.NewTestClient("dev-token", "123", "", srv.URL, srv.Client())
+
+	result, _, err := getBidLandscape(context.Background(), client, getBidLandscapeInput{
+		Keywords:     []string{"golang"},
+		MinCPCMicros: 1_000_000,
+		MaxCPCMicros: 3_000_000,
+		Steps:        3,
+	})
+	if err != nil {
+		t.Fatalf("unexpected protocol error: %v", err)
+	}
+
+	var resp keywordplanner.BidLandscapeResponse
+	if err := json.Unmarshal([]byte(result.Content[0].(*mcp.TextContent).Text), &resp); err != nil {
+		t.Fatalf("unmarshal result: %v", err)
+	}
+	if len(resp.Points) != 3 || resp.Points[1].MaxCPCMicros != 2_000_000 {
+		t.Errorf("Points = %+v, want bids 1000000, 2000000, 3000000", resp.Points)
+	}
+}
+
+// TestGetBidLandscape_InvalidBids_ReturnsValidationError verifies the bid list and
+// range arguments are validated before any forecast is requested.
+func TestGetBidLandscape_InvalidBids_ReturnsValidationError(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name  string
+		input getBidLandscapeInput
+		want  string
+	}{
+		{
+			name:  "no bids",
+			input: getBidLandscapeInput{Keywords: []string{"go"}},
+			want:  "either bids_micros or both min_cpc_micros and max_cpc_micros must be provided",
+		},
+		{
+			name:  "list and range",
+			input: getBidLandscapeInput{Keywords: []string{"go"}, BidsMicros: []int64{1_000_000}, MinCPCMicros: 500_000},
+			want:  "bids_micros cannot be combined",
+		},
+		{
+			name:  "negative bid",
+			input: getBidLandscapeInput{Keywords: []string{"go"}, BidsMicros: []int64{-1}},
+			want:  "bids_micros must be positive",
+		},
+		{
+			name:  "no keywords",
+			input: getBidLandscapeInput{BidsMicros: []int64{1_000_000}},
+			want:  "at least one of keywords",
+		},
+	}
+
+	client := keywordplanner.NewTestClient("dev-token", "123", "", "http://127.0.0.1:0", http.DefaultClient)
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			t.Parallel()
+			result, _, err := getBidLandscape(context.Background(), client, test.input)
+			if err != nil {
+				t.Fatalf("unexpected protocol error: %v", err)
+			}
+			text := result.Content[0].(*mcp.TextContent).Text
+			if !strings.Contains(text, test.want) {
+				t.Errorf("result text = %q, want it to mention %q", text, test.want)
+			}
+		})
+	}
+}
+
+// TestPlanForBudget_MissingBudget_ReturnsValidationError verifies budget_micros is
+// required before any forecast is requested.
+func TestPlanForBudget_MissingBudget_ReturnsValidationError(t *testing.T) {
+	t.Parallel()
+
+	client := keywordplanner.NewTestClient("dev-token", "123", "", "http://127.0.0.1:0", http.DefaultClient)
+
+	result, _, err := planForBudget(context.Background(), client, planForBudgetInput{Keywords: []string{"go"}})
+	if err != nil {
+		t.Fatalf("unexpected protocol error: %v", err)
+	}
+
+	text := result.Content[0].(*mcp.TextContent).Text
+	if !strings.Contains(text, "budget_micros must be positive") {
+		t.Errorf("result text = %q, want it to mention %q", text, "budget_micros must be positive")
+	}
+}
+
+// TestPlanForBudget_AmpleBudget_ReturnsPlan verifies the handler returns the plan
+// from Client.PlanForBudget.
+func TestPlanForBudget_AmpleBudget_ReturnsPlan(t *testing.T) {
+	t.Parallel()
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{"campaignForecastMetrics": {"clicks": 10, "costMicros": 5000000}}`))
+	}))
+	defer srv.Close()
+
+	client := keywordplanner.NewTestClient("dev-token", "123", "", srv.URL, srv.Client())
+
+	result, _, err := planForBudget(context.Background(), client, planForBudgetInput{
+		Keywords:     []string{"golang"},
+		BudgetMicros: 100_000_000,
+		MaxCPCMicros: 4_000_000,
+	})
+	if err != nil {
+		t.Fatalf("unexpected protocol error: %v", err)
+	}
+
+	var resp keywordplanner.BudgetPlanResponse
+	if err := json.Unmarshal([]byte(result.Content[0].(*mcp.TextContent).Text), &resp); err != nil {
+		t.Fatalf("unmarshal result: %v", err)
+	}
+	if resp.MaxCPCMicros != 4_000_000 || resp.BudgetLimited {
+		t.Errorf("plan = bid %d, budgetLimited %v; want bid 4000000, not budget limited", resp.MaxCPCMicros, resp.BudgetLimited)
+	}
+}
+
+// TestResolveCachedTools_DefaultsToAllAndRejectsUnknown verifies an empty list caches
+// every tool and a misspelt tool name is an error.
+func TestResolveCachedTools_DefaultsToAllAndRejectsUnknown(t *testing.T) {
+	t.Parallel()
+
+	all, err := resolveCachedTools(nil)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	for _, name := range toolNames {
+		if !all[name] {
+			t.Errorf("%s is not cached by default", name)
+		}
+	}
+	if _, err := resolveCachedTools([]string{"get_historical_metric"}); err == nil {
+		t.Error("expected an error for an unknown tool, got nil")
+	}
+}
+
+// TestNewServer_CachedTools_MarkRepeatedCallsCached verifies a tool enabled in
+// CachedTools serves a repeated call from the cache and says so, while other tools
+// always call the API.
+func TestNewServer_CachedTools_MarkRepeatedCallsCached(t *testing.T) {
+	t.Parallel()
+
+	var requests atomic.Int32
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests.Add(1)
+		w.Header().Set("Content-Type", "application/json")
+		if strings.HasSuffix(r.URL.Path, ":generateKeywordIdeas") {
+			_, _ = w.Write([]byte(`{"results": []}`))
+			return
+		}
+		_, _ = w.Write([]byte(`{"metrics": []}`))
+	}))
+	defer srv.Close()
+
+	client := keywordplanner.NewTestClient("dev-token", "123", "", srv.URL, srv.Client())
+	client.SetCache(keywordplanner.CacheOptions{MaxEntries: 10, TTL: time.Hour})
+	mcpServer := newServer(client, serverOptions{CachedTools: map[string]bool{"get_historical_metrics": true}})
+
+	ctx := context.Background()
+	clientTransport, serverTransport := mcp.NewInMemoryTransports()
+	serverSession, err := mcpServer.Connect(ctx, serverTransport, nil)
+	if err != nil {
+		t.Fatalf("server.Connect: %v", err)
+	}
+	defer serverSession.Close()
+	mcpClient := mcp.NewClient(&mcp.Implementation{Name: "test-client", Version: "test"}, nil)
+	clientSession, err := mcpClient.Connect(ctx, clientTransport, nil)
+	if err != nil {
+		t.Fatalf("client.Connect: %v", err)
+	}
+	defer clientSession.Close()
+
+	call := func(name string, args map[string]any) map[string]any {
+		t.Helper()
+		result, err := clientSession.CallTool(ctx, &mcp.CallToolParams{Name: name, Arguments: args})
+		if err != nil {
+			t.Fatalf("CallTool: %v", err)
+		}
+		if result.IsError {
+			t.Fatalf("CallTool returned an error result: %+v", result.Content)
+		}
+		var body map[string]any
+		if err := json.Unmarshal([]byte(result.Content[0].(*mcp.TextContent).Text), &body); err != nil {
+			t.Fatalf("unmarshal result: %v", err)
+		}
+		return body
+	}
+
+	metricsArgs := map[string]any{"keywords": []string{"go"}}
+	if first := call("get_historical_metrics", metricsArgs); first["cached"] != nil {
+		t.Errorf("first call cached = %v, want it absent", first["cached"])
+	}
+	second := call("get_historical_metrics", metricsArgs)
+	if second["cached"] != true || second["fetched_at"] == nil {
+		t.Errorf("second call cached = %v, fetched_at = %v; want true and a time", second["cached"], second["fetched_at"])
+	}
+
+	ideasArgs := map[string]any{"seed_keywords": []string{"go"}}
+	call("generate_keyword_ideas", ideasArgs)
+	if again := call("generate_keyword_ideas", ideasArgs); again["cached"] != nil {
+		t.Errorf("uncached tool reported cached = %v", again["cached"])
+	}
+	if n := requests.Load(); n != 3 {
+		t.Errorf("API requests = %d, want 3", n)
+	}
+}
+
 // TestNewServer_CallHistoricalMetricsTool_ViaRealSession confirms the
 // get_historical_metrics tool, as actually registered by newServer (not just
 // the underlying Go function called directly), works end-to-end through a
@@ -419,7 +1072,7 @@ func TestNewServer_CallHistoricalMetricsTool_ViaRealSession(t *testing.T) {
 	defer srv.Close()
 
 	client := keywordplanner.NewTestClient("dev-token", "123", "", srv.URL, srv.Client())
-	mcpServer := newServer(client)
+	mcpServer := newServer(client, serverOptions{})
 
 	ctx := context.Background()
 	clientTransport, serverTransport := mcp.NewInMemoryTransports()
@@ -462,7 +1115,7 @@ func TestNewServer_CallKeywordForecastTool_ViaRealSession(t *testing.T) {
 	defer srv.Close()
 
 	client := keywordplanner.NewTestClient("dev-token", "123", "", srv.URL, srv.Client())
-	mcpServer := newServer(client)
+	mcpServer := newServer(client, serverOptions{})
 
 	ctx := context.Background()
 	clientTransport, serverTransport := mcp.NewInMemoryTransports()
@@ -0,0 +1,87 @@
+package main
+
+import (
+	"strings"
+
+	"github.com/ncosentino/google-keyword-planner-mcp/go/internal/keywordplanner"
+)
+
+// troubleshootingURL is the published troubleshooting guide the remediation hints
+// are drawn from.
+const troubleshootingURL = "https://www.devleader.ca/projects/google-keyword-planner-mcp/troubleshooting/"
+
+// remediation is how to resolve one known Google Ads failure.
+type remediation struct {
+	hint string
+	// loginCustomerIDHint, when set, replaces hint for errors whose message mentions
+	// the login-customer-id header, which point at the manager account setting
+	// rather than the OAuth user.
+	loginCustomerIDHint string
+}
+
+// remediations maps Google Ads error codes, and the RPC statuses used when a
+// response has no GoogleAdsFailure, to the fixes in docs/troubleshooting.md.
+var remediations = map[string]remediation{
+	"DEVELOPER_TOKEN_NOT_APPROVED": {
+		hint: "The developer token in GOOGLE_ADS_DEVELOPER_TOKEN is in test mode and can only access test accounts. " +
+			"Apply for Basic access at https://ads.google.com/aw/apicenter (Google reviews within a few days), " +
+			"or point GOOGLE_ADS_CUSTOMER_ID at a Google Ads test account meanwhile.",
+	},
+	"DEVELOPER_TOKEN_PROHIBITED": {
+		hint: "The developer token in GOOGLE_ADS_DEVELOPER_TOKEN is not allowed to call this Google Cloud project's API. " +
+			"Use the developer token issued to the manager account that owns the OAuth client.",
+	},
+	"USER_PERMISSION_DENIED": {
+		hint: "The Google account behind GOOGLE_ADS_REFRESH_TOKEN cannot access the account in GOOGLE_ADS_CUSTOMER_ID. " +
+			"Re-run the OAuth refresh token flow as a Google account that owns or has access to that ads account.",
+		loginCustomerIDHint: "GOOGLE_ADS_CUSTOMER_ID is a managed sub-account, but GOOGLE_ADS_LOGIN_CUSTOMER_ID is missing or wrong. " +
+			"Set GOOGLE_ADS_LOGIN_CUSTOMER_ID to the ID of the manager account it is accessed through.",
+	},
+	"INVALID_LOGIN_CUSTOMER_ID_SERVING_CUSTOMER_ID_COMBINATION": {
+		hint: "GOOGLE_ADS_LOGIN_CUSTOMER_ID is not a manager of GOOGLE_ADS_CUSTOMER_ID. " +
+			"Set it to the manager account the customer is linked to, or unset it for a standalone account.",
+	},
+	"INVALID_LOGIN_CUSTOMER_ID": {
+		hint: "GOOGLE_ADS_LOGIN_CUSTOMER_ID is not a valid customer ID. Set it to the 10-digit manager account ID, or unset it.",
+	},
+	"INVALID_CUSTOMER_ID": {
+		hint: "GOOGLE_ADS_CUSTOMER_ID is not a valid customer ID. It must contain only the 10 digits of the account ID; " +
+			"dashes are stripped automatically, but other characters are not.",
+	},
+	"CUSTOMER_NOT_ENABLED": {
+		hint: "The account in GOOGLE_ADS_CUSTOMER_ID is not enabled, which usually means it has no billing set up. " +
+			"Add a payment method under Billing & Payments in the Google Ads UI; no ads need to run.",
+	},
+	"OAUTH_TOKEN_EXPIRED": {
+		hint: "The OAuth token has expired. Generate a new GOOGLE_ADS_REFRESH_TOKEN with the OAuth refresh token flow.",
+	},
+	"OAUTH_TOKEN_REVOKED": {
+		hint: "The OAuth token was revoked. Generate a new GOOGLE_ADS_REFRESH_TOKEN with the OAuth refresh token flow.",
+	},
+	"INVALID_ARGUMENT": {
+		hint: "The API rejected the request. This is often a malformed GOOGLE_ADS_CUSTOMER_ID (digits only) " +
+			"or a tool argument the API does not accept; check the error message.",
+	},
+}
+
+// remediationHint returns the catalogued fix for apiErr, checking each Google Ads
+// error code in order and then the RPC status. It returns "" when none is known.
+func remediationHint(apiErr *keywordplanner.APIError) string {
+	for _, e := range apiErr.Errors {
+		if r, ok := remediations[e.Code]; ok {
+			if r.loginCustomerIDHint != "" && mentionsLoginCustomerID(e.Message) {
+				return r.loginCustomerIDHint
+			}
+			return r.hint
+		}
+	}
+	if r, ok := remediations[apiErr.Status]; ok {
+		return r.hint
+	}
+	return ""
+}
+
+func mentionsLoginCustomerID(message string) bool {
+	m := strings.ToLower(message)
+	return strings.Contains(m, "login-customer-id") || strings.Contains(m, "login_customer_id")
+}
@@ -0,0 +1,98 @@
+package main
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/ncosentino/google-keyword-planner-mcp/go/internal/keywordplanner"
+)
+
+// TestRemediationHint_KnownFailures verifies each failure in the troubleshooting
+// guide resolves to a hint naming the setting to change.
+func TestRemediationHint_KnownFailures(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name   string
+		apiErr keywordplanner.APIError
+		want   string
+	}{
+		{
+			name:   "developer token not approved",
+			apiErr: keywordplanner.APIError{Errors: []keywordplanner.GoogleAdsError{{Code: "DEVELOPER_TOKEN_NOT_APPROVED"}}},
+			want:   "apicenter",
+		},
+		{
+			name: "permission denied mentioning login-customer-id",
+			apiErr: keywordplanner.APIError{Errors: []keywordplanner.GoogleAdsError{{
+				Code:    "USER_PERMISSION_DENIED",
+				Message: "User doesn't have permission to access customer. Note: If you're accessing a client customer, the manager's customer id must be set in the 'login-customer-id' header.",
+			}}},
+			want: "GOOGLE_ADS_LOGIN_CUSTOMER_ID",
+		},
+		{
+			name:   "permission denied for the OAuth user",
+			apiErr: keywordplanner.APIError{Errors: []keywordplanner.GoogleAdsError{{Code: "USER_PERMISSION_DENIED", Message: "User doesn't have permission to access customer."}}},
+			want:   "GOOGLE_ADS_REFRESH_TOKEN",
+		},
+		{
+			name:   "billing not set up",
+			apiErr: keywordplanner.APIError{Errors: []keywordplanner.GoogleAdsError{{Code: "CUSTOMER_NOT_ENABLED"}}},
+			want:   "Billing & Payments",
+		},
+		{
+			name:   "invalid argument status",
+			apiErr: keywordplanner.APIError{Status: "INVALID_ARGUMENT"},
+			want:   "GOOGLE_ADS_CUSTOMER_ID",
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			t.Parallel()
+			if hint := remediationHint(&test.apiErr); !strings.Contains(hint, test.want) {
+				t.Errorf("remediationHint = %q, want it to mention %q", hint, test.want)
+			}
+		})
+	}
+}
+
+func TestRemediationHint_UnknownCode_ReturnsEmpty(t *testing.T) {
+	t.Parallel()
+
+	apiErr := keywordplanner.APIError{Status: "INTERNAL", Errors: []keywordplanner.GoogleAdsError{{Code: "SOMETHING_NEW"}}}
+	if hint := remediationHint(&apiErr); hint != "" {
+		t.Errorf("remediationHint = %q, want empty", hint)
+	}
+}
+
+// TestGetKeywordForecast_KnownFailure_AttachesRemediation verifies tool error
+// results carry the catalogued hint and a link to the troubleshooting guide.
+func TestGetKeywordForecast_KnownFailure_AttachesRemediation(t *testing.T) {
+	t.Parallel()
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusForbidden)
+		_, _ = w.Write([]byte(permissionDeniedBody))
+	}))
+	defer srv.Close()
+
+	client := keywordplanner.NewTestClient("dev-token", "123", "", srv.URL, srv.Client())
+
+	result, _, err := getKeywordForecast(context.Background(), client, getKeywordForecastInput{Keywords: []string{"go"}})
+	if err != nil {
+		t.Fatalf("unexpected protocol error: %v", err)
+	}
+
+	te := decodeToolError(t, result)
+	if !strings.Contains(te.Hint, "GOOGLE_ADS_REFRESH_TOKEN") {
+		t.Errorf("hint = %q, want the USER_PERMISSION_DENIED remediation", te.Hint)
+	}
+	if te.DocsURL != troubleshootingURL {
+		t.Errorf("docs_url = %q, want %q", te.DocsURL, troubleshootingURL)
+	}
+}
@@ -18,7 +18,7 @@ import (
 // os.Stdin/os.Stdout, so it cannot be pointed at a test double directly.
 // StdioTransport.Connect is, however, byte-for-byte mcp.IOTransport.Connect
 // with os.Stdin/os.Stdout substituted in -- same newline-delimited JSON
-// framing, same connection type -- so wiring newServer(client) through
+// framing, same connection type -- so wiring newServer(client, serverOptions{}) through
 // IOTransport over real in-process pipes exercises the identical
 // framing/protocol code stdio uses in production, without spawning a
 // subprocess. Before this test, nothing automated exercised the stdio code
@@ -34,7 +34,7 @@ func TestStdioTransport_ServesRealSession(t *testing.T) {
 	defer apiSrv.Close()
 
 	client := keywordplanner.NewTestClient("dev-token", "123", "", apiSrv.URL, apiSrv.Client())
-	srv := newServer(client)
+	srv := newServer(client, serverOptions{})
 
 	serverRead, clientWrite := io.Pipe()
 	clientRead, serverWrite := io.Pipe()
@@ -58,8 +58,8 @@ func TestStdioTransport_ServesRealSession(t *testing.T) {
 	if err != nil {
 		t.Fatalf("ListTools over stdio-equivalent transport: %v", err)
 	}
-	if len(toolsResult.Tools) != 3 {
-		t.Errorf("got %d tools over stdio-equivalent transport, want 3", len(toolsResult.Tools))
+	if len(toolsResult.Tools) != 5 {
+		t.Errorf("got %d tools over stdio-equivalent transport, want 5", len(toolsResult.Tools))
 	}
 
 	callResult, err := clientSession.CallTool(ctx, &mcp.CallToolParams{
@@ -12,9 +12,11 @@ import (
 // repair; it is intentionally a plain data map (not per-tool duplicated logic),
 // so every tool with a keyword-list parameter is covered by one code path.
 var toolArrayFields = map[string][]string{
-	"generate_keyword_ideas": {"seed_keywords"},
+	"generate_keyword_ideas": {"seed_keywords", "locations"},
 	"get_historical_metrics": {"keywords"},
-	"get_keyword_forecast":   {"keywords"},
+	"get_keyword_forecast":   {"keywords", "keyword_specs", "ad_groups", "locations", "languages", "negative_keywords"},
+	"get_bid_landscape":      {"keywords", "keyword_specs", "ad_groups", "bids_micros", "locations", "languages", "negative_keywords"},
+	"plan_for_budget":        {"keywords", "keyword_specs", "ad_groups", "locations", "languages", "negative_keywords"},
 }
 
 // coerceStringifiedArrayArgs returns a receiving middleware that repairs a
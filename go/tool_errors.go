@@ -0,0 +1,86 @@
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+	"github.com/ncosentino/google-keyword-planner-mcp/go/internal/keywordplanner"
+)
+
+// Codes for tool errors that do not come from a Google Ads API error response.
+const (
+	errorCodeInvalidInput  = "INVALID_INPUT"
+	errorCodeRequestFailed = "REQUEST_FAILED"
+	errorCodeQuotaBudget   = "QUOTA_BUDGET_EXHAUSTED"
+)
+
+// toolError is the error object every tool returns, as {"error": {...}} JSON text
+// content, for validation and upstream failures. These are tool-level errors the
+// model can read and act on, not protocol errors. Code is the Google Ads error code
+// for API failures, so callers can branch on it without parsing Message.
+type toolError struct {
+	Code      string `json:"code"`
+	Message   string `json:"message"`
+	RequestID string `json:"request_id,omitempty"`
+	Hint      string `json:"hint,omitempty"`
+	DocsURL   string `json:"docs_url,omitempty"`
+}
+
+// errorResult reports invalid tool arguments.
+func errorResult(message string) *mcp.CallToolResult {
+	return toolErrorResult(toolError{Code: errorCodeInvalidInput, Message: message})
+}
+
+// upstreamErrorResult reports a failed client call, described by action (e.g.
+// "getting keyword forecast"). Google Ads API errors carry their error code, request
+// ID and a hint on how to resolve them: the catalogued remediation for known failures,
+// with a link to the troubleshooting guide, or else a generic hint for the status.
+func upstreamErrorResult(action string, err error) *mcp.CallToolResult {
+	te := toolError{Code: errorCodeRequestFailed, Message: fmt.Sprintf("%s: %v", action, err)}
+	var quotaErr *keywordplanner.QuotaExhaustedError
+	if errors.As(err, &quotaErr) {
+		te.Code = errorCodeQuotaBudget
+		te.Hint = "This server's daily Google Ads operation budget is used up, so the request was not sent. " +
+			"Wait until it resets, or raise GOOGLE_ADS_DAILY_OPERATIONS if the developer token's access level allows more."
+	}
+	var apiErr *keywordplanner.APIError
+	if errors.As(err, &apiErr) {
+		if code := apiErr.Code(); code != "" {
+			te.Code = code
+		}
+		te.RequestID = apiErr.RequestID
+		if te.Hint = remediationHint(apiErr); te.Hint != "" {
+			te.DocsURL = troubleshootingURL
+		} else {
+			te.Hint = apiErrorHint(apiErr)
+		}
+	}
+	return toolErrorResult(te)
+}
+
+func toolErrorResult(te toolError) *mcp.CallToolResult {
+	b, _ := json.Marshal(map[string]toolError{"error": te})
+	return &mcp.CallToolResult{Content: []mcp.Content{&mcp.TextContent{Text: string(b)}}}
+}
+
+// apiErrorHint suggests a next step for apiErr based on its RPC status, or its HTTP
+// status when the response was not a Google API error.
+func apiErrorHint(apiErr *keywordplanner.APIError) string {
+	switch {
+	case apiErr.Status == "UNAUTHENTICATED" || apiErr.HTTPStatus == http.StatusUnauthorized:
+		return "Check the OAuth client ID, client secret and refresh token; the refresh token may have expired or been revoked."
+	case apiErr.Status == "PERMISSION_DENIED" || apiErr.HTTPStatus == http.StatusForbidden:
+		return "Check that the developer token is approved and that the OAuth user can access the customer account."
+	case apiErr.Status == "RESOURCE_EXHAUSTED" || apiErr.HTTPStatus == http.StatusTooManyRequests:
+		return "The Google Ads API quota is exhausted; wait before retrying."
+	case apiErr.Status == "INVALID_ARGUMENT" || apiErr.HTTPStatus == http.StatusBadRequest:
+		return "The API rejected the request; check the tool arguments against the error message."
+	case apiErr.HTTPStatus >= http.StatusInternalServerError:
+		return "The Google Ads API failed temporarily; retry the call."
+	default:
+		return ""
+	}
+}
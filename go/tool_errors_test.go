@@ -0,0 +1,116 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+	"github.com/ncosentino/google-keyword-planner-mcp/go/internal/keywordplanner"
+)
+
+// permissionDeniedBody is a GoogleAdsFailure response as returned over REST.
+const permissionDeniedBody = `{
+  "error": {
+    "code": 403,
+    "message": "The caller does not have permission",
+    "status": "PERMISSION_DENIED",
+    "details": [{
+      "@type": "type.googleapis.com/google.ads.googleads.v23.errors.GoogleAdsFailure",
+      "errors": [{
+        "errorCode": {"authorizationError": "USER_PERMISSION_DENIED"},
+        "message": "User doesn't have permission to access customer."
+      }],
+      "requestId": "req-123"
+    }]
+  }
+}`
+
+// decodeToolError extracts the {"error": {...}} object from a tool result.
+func decodeToolError(t *testing.T, result *mcp.CallToolResult) toolError {
+	t.Helper()
+	var body struct {
+		Error toolError `json:"error"`
+	}
+	if err := json.Unmarshal([]byte(result.Content[0].(*mcp.TextContent).Text), &body); err != nil {
+		t.Fatalf("unmarshal tool error: %v", err)
+	}
+	return body.Error
+}
+
+// TestUpstreamErrorResult_APIError_IsStructured verifies a GoogleAdsFailure reaches
+// the tool result as its error code, request ID and a hint.
+func TestUpstreamErrorResult_APIError_IsStructured(t *testing.T) {
+	t.Parallel()
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusForbidden)
+		_, _ = w.Write([]byte(permissionDeniedBody))
+	}))
+	defer srv.Close()
+
+	client := keywordplanner.NewTestClient("dev-token", "123", "", srv.URL, srv.Client())
+
+	result, _, err := getHistoricalMetrics(context.Background(), client, getHistoricalMetricsInput{Keywords: []string{"go"}})
+	if err != nil {
+		t.Fatalf("unexpected protocol error: %v", err)
+	}
+
+	te := decodeToolError(t, result)
+	if te.Code != "USER_PERMISSION_DENIED" {
+		t.Errorf("code = %q, want USER_PERMISSION_DENIED", te.Code)
+	}
+	if te.RequestID != "req-123" {
+		t.Errorf("request_id = %q, want req-123", te.RequestID)
+	}
+	if te.Hint == "" {
+		t.Error("hint is empty, want a remediation hint")
+	}
+}
+
+// TestErrorResult_ValidationError_HasInvalidInputCode verifies argument validation
+// failures use the INVALID_INPUT code and carry no request ID.
+func TestErrorResult_ValidationError_HasInvalidInputCode(t *testing.T) {
+	t.Parallel()
+
+	client := keywordplanner.NewTestClient("dev-token", "123", "", "http://127.0.0.1:0", http.DefaultClient)
+
+	result, _, err := getHistoricalMetrics(context.Background(), client, getHistoricalMetricsInput{
+		Keywords:   []string{"go"},
+		StartMonth: "2024-01",
+	})
+	if err != nil {
+		t.Fatalf("unexpected protocol error: %v", err)
+	}
+
+	te := decodeToolError(t, result)
+	if te.Code != errorCodeInvalidInput || te.RequestID != "" {
+		t.Errorf("error = %+v, want code %s and no request ID", te, errorCodeInvalidInput)
+	}
+}
+
+// TestUpstreamErrorResult_QuotaExhausted_HasQuotaCode verifies a request refused by
+// the daily operation budget is reported with its own code and a hint.
+func TestUpstreamErrorResult_QuotaExhausted_HasQuotaCode(t *testing.T) {
+	t.Parallel()
+
+	client := keywordplanner.NewTestClient("dev-token", "123", "", "http://127.0.0.1:0", http.DefaultClient)
+	if err := client.SetLimits(keywordplanner.Limits{DailyOperations: 1}); err != nil {
+		t.Fatalf("SetLimits: %v", err)
+	}
+	// The first request uses up the budget, whether or not it succeeds.
+	_, _, _ = getHistoricalMetrics(context.Background(), client, getHistoricalMetricsInput{Keywords: []string{"go"}})
+
+	result, _, err := getHistoricalMetrics(context.Background(), client, getHistoricalMetricsInput{Keywords: []string{"go"}})
+	if err != nil {
+		t.Fatalf("unexpected protocol error: %v", err)
+	}
+
+	te := decodeToolError(t, result)
+	if te.Code != errorCodeQuotaBudget || te.Hint == "" {
+		t.Errorf("error = %+v, want code %s and a hint", te, errorCodeQuotaBudget)
+	}
+}